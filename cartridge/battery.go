@@ -0,0 +1,82 @@
+package cartridge
+
+import "errors"
+
+// ErrSaveSize is returned when save data does not match the size of the
+// external RAM of a cartridge.
+var ErrSaveSize = errors.New("save data does not match external RAM size")
+
+// Battery returns whether the external RAM of the cartridge, and its
+// real-time clock if it has one, are kept alive by a battery and should be
+// saved between runs.
+func (c *Cartridge) Battery() bool {
+	return c.battery && (c.ram != nil || c.rtc != nil)
+}
+
+// Store hands the provided value over to the memory bank controller, marking
+// the save data as modified when the controller reports that the write changed
+// battery-backed state. Writes the controller drops, such as those made while
+// external RAM is disabled, leave the save data as it is.
+func (c *Cartridge) Store(addr uint16, b uint8) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.BankController.Store(addr, b) {
+		c.dirty = true
+	}
+}
+
+// Dirty returns whether the save data has been modified since it was last
+// returned by SaveData or restored by LoadSaveData.
+func (c *Cartridge) Dirty() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.dirty
+}
+
+// SaveData returns a copy of the external RAM, followed by the state of the
+// real-time clock for cartridges that have one.
+func (c *Cartridge) SaveData() []uint8 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := make([]uint8, len(c.ram))
+	copy(data, c.ram)
+	if c.rtc != nil {
+		data = append(data, c.rtc.marshal()...)
+	}
+	if c.huc3Clock != nil {
+		data = append(data, c.huc3Clock.marshal()...)
+	}
+	c.dirty = false
+
+	return data
+}
+
+// LoadSaveData restores the external RAM and the real-time clock from the
+// provided save data, as returned by SaveData. A missing clock state is
+// tolerated, and leaves the clock running from the current time.
+func (c *Cartridge) LoadSaveData(data []uint8) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(data) < len(c.ram) {
+		return ErrSaveSize
+	}
+
+	switch footer := data[len(c.ram):]; {
+	case len(footer) == 0:
+	case c.rtc != nil && (len(footer) == rtcFooterSize || len(footer) == rtcFooterSizeLegacy):
+		c.rtc.unmarshal(footer)
+	case c.huc3Clock != nil && len(footer) == huc3FooterSize:
+		c.huc3Clock.unmarshal(footer)
+	default:
+		return ErrSaveSize
+	}
+
+	copy(c.ram, data)
+	c.dirty = false
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package cartridge
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveData(t *testing.T) {
+	cart, err := New(NewROM(0x03, 0x8000, 0x02))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	if !cart.Battery() {
+		t.Fatal("got no battery, expected battery")
+	}
+
+	// Writes made while external RAM is disabled are dropped.
+	cart.Store(0xA123, 0x45)
+	if cart.Dirty() {
+		t.Error("got dirty with RAM disabled, expected clean")
+	}
+
+	cart.Store(0x0000, 0x0A)
+	if cart.Dirty() {
+		t.Error("got dirty, expected clean")
+	}
+	cart.Store(0xA123, 0x00)
+	if cart.Dirty() {
+		t.Error("got dirty after writing the same value, expected clean")
+	}
+	cart.Store(0xA123, 0x45)
+	if !cart.Dirty() {
+		t.Error("got clean, expected dirty")
+	}
+
+	data := cart.SaveData()
+	if len(data) != 0x2000 || data[0x123] != 0x45 {
+		t.Fatalf("got %d bytes, expected 8192 holding 0x45 at 0x123", len(data))
+	}
+	if cart.Dirty() {
+		t.Error("got dirty after save, expected clean")
+	}
+
+	restored, _ := New(NewROM(0x03, 0x8000, 0x02))
+	if err := restored.LoadSaveData(data); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+	restored.Store(0x0000, 0x0A)
+	if b := restored.Load(0xA123); b != 0x45 {
+		t.Errorf("got 0x%02X, expected 0x45", b)
+	}
+
+	if err := restored.LoadSaveData(data[:0x1000]); err != ErrSaveSize {
+		t.Errorf("got %v, expected %v", err, ErrSaveSize)
+	}
+}
+
+// Cartridges whose type does not mention RAM, but which carry battery-backed
+// RAM nevertheless.
+func TestSaveDataImplicitRAM(t *testing.T) {
+	var testCases = []struct {
+		cartType Type
+		ramSize  uint8
+		size     int
+	}{
+		{0xFC, 0x04, 0x20000},
+		{0xFE, 0x03, 0x8000 + huc3FooterSize},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cartType.String(), func(t *testing.T) {
+			cart, err := New(NewROM(tc.cartType, 0x100000, tc.ramSize))
+			if err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+			if !cart.Battery() {
+				t.Fatal("got no battery, expected battery")
+			}
+
+			cart.Store(0x0000, 0x0A)
+			cart.Store(0xA123, 0x45)
+			data := cart.SaveData()
+			if len(data) != tc.size || data[0x123] != 0x45 {
+				t.Fatalf("got %d bytes, expected %d holding 0x45 at 0x123", len(data), tc.size)
+			}
+
+			restored, _ := New(NewROM(tc.cartType, 0x100000, tc.ramSize))
+			if err := restored.LoadSaveData(data); err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+			restored.Store(0x0000, 0x0A)
+			if b := restored.Load(0xA123); b != 0x45 {
+				t.Errorf("got 0x%02X, expected 0x45", b)
+			}
+		})
+	}
+}
+
+func TestSaveDataRTC(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1600000000, 0)}
+	cart := newMBC3Cartridge(t, clock)
+
+	cart.Store(0x4000, rtcHours)
+	cart.Store(0xA000, 5)
+	clock.advance(10 * time.Second)
+	latchRTC(cart)
+
+	data := cart.SaveData()
+	if len(data) != 0x8000+rtcFooterSize {
+		t.Fatalf("got %d bytes, expected %d", len(data), 0x8000+rtcFooterSize)
+	}
+
+	// The clock keeps counting while the emulator is not running.
+	clock.advance(2 * time.Hour)
+	restored := newMBC3Cartridge(t, clock)
+	if err := restored.LoadSaveData(data); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	restored.Store(0x4000, rtcHours)
+	if b := restored.Load(0xA000); b != 5 {
+		t.Errorf("latched hours: got %d, expected 5", b)
+	}
+
+	latchRTC(restored)
+	for reg, expected := range map[uint8]uint8{rtcSeconds: 10, rtcHours: 7} {
+		restored.Store(0x4000, reg)
+		if b := restored.Load(0xA000); b != expected {
+			t.Errorf("register 0x%02X: got %d, expected %d", reg, b, expected)
+		}
+	}
+
+	// Footers with a 32-bit timestamp are accepted as well.
+	if err := restored.LoadSaveData(data[:len(data)-4]); err != nil {
+		t.Errorf("legacy footer: got %v, expected nil", err)
+	}
+}
+
+func TestSaveDataHuC3Clock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1600000000, 0)}
+	cart, err := New(NewROM(0xFE, 0x100000, 0x03), WithClock(clock))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	clock.advance(2*24*time.Hour + 90*time.Minute)
+	huc3Command(cart, huc3CommandAddrLow, 0x0)
+	huc3Command(cart, huc3CommandAddrHigh, 0x1)
+	huc3Command(cart, huc3CommandWrite, 0x7)
+
+	data := cart.SaveData()
+	if len(data) != 0x8000+huc3FooterSize {
+		t.Fatalf("got %d bytes, expected %d", len(data), 0x8000+huc3FooterSize)
+	}
+
+	// The clock keeps counting while the emulator is not running.
+	clock.advance(2 * time.Hour)
+	restored, _ := New(NewROM(0xFE, 0x100000, 0x03), WithClock(clock))
+	if err := restored.LoadSaveData(data); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	if minutes, days := huc3Time(restored); minutes != 210 || days != 2 {
+		t.Errorf("got %d minutes and %d days, expected 210 minutes and 2 days", minutes, days)
+	}
+	huc3Command(restored, huc3CommandAddrLow, 0x0)
+	huc3Command(restored, huc3CommandAddrHigh, 0x1)
+	if n := huc3Command(restored, huc3CommandRead, 0); n != 0x7 {
+		t.Errorf("memory: got 0x%X, expected 0x7", n)
+	}
+}
+
+// Writes to a clock change its state even when they read back the same.
+func TestDirtyClock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1600000000, 0)}
+
+	cart := newMBC3Cartridge(t, clock)
+	cart.Store(0x4000, rtcSeconds)
+	cart.SaveData()
+	cart.Store(0xA000, cart.Load(0xA000))
+	if !cart.Dirty() {
+		t.Error("MBC3 seconds: got clean, expected dirty")
+	}
+
+	cart, err := New(NewROM(0xFE, 0x100000, 0x03), WithClock(clock))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+	huc3Command(cart, huc3CommandAddrLow, 0x0)
+	if cart.Dirty() {
+		t.Error("HuC3 address: got dirty, expected clean")
+	}
+	huc3Command(cart, huc3CommandWrite, 0x0)
+	if !cart.Dirty() {
+		t.Error("HuC3 write: got clean, expected dirty")
+	}
+}
+
+func TestSaver(t *testing.T) {
+	path := SavePath(filepath.Join(t.TempDir(), "game.gb"))
+	if filepath.Base(path) != "game.sav" {
+		t.Errorf("got %s, expected game.sav", filepath.Base(path))
+	}
+
+	cart, _ := New(NewROM(0x03, 0x8000, 0x02))
+	saver := NewSaver(cart, path, time.Hour)
+	if err := saver.Load(); err != nil {
+		t.Fatalf("missing file: got %v, expected nil", err)
+	}
+
+	cart.Store(0x0000, 0x0A)
+	cart.Store(0xA000, 0x12)
+	if err := saver.MaybeFlush(); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("got save file before the flush interval, expected none")
+	}
+
+	if err := saver.Flush(); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	restored, _ := New(NewROM(0x03, 0x8000, 0x02))
+	if err := NewSaver(restored, path, time.Hour).Load(); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+	restored.Store(0x0000, 0x0A)
+	if b := restored.Load(0xA000); b != 0x12 {
+		t.Errorf("got 0x%02X, expected 0x12", b)
+	}
+}
+
+func TestReset(t *testing.T) {
+	var testCases = []struct {
+		cartType Type
+		keep     bool
+		expected uint8
+	}{
+		{0x03, true, 0x45},
+		{0x03, false, 0x00},
+		{0x02, true, 0x00},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("type=%s keep=%t", tc.cartType, tc.keep), func(t *testing.T) {
+			cart, err := New(NewROM(tc.cartType, 0x20000, 0x02))
+			if err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+
+			cart.Store(0x0000, 0x0A)
+			cart.Store(0x2000, 0x05)
+			cart.Store(0xA000, 0x45)
+
+			cart.Reset(tc.keep)
+			if bankN := cart.Load(0x4000); bankN != 0x01 {
+				t.Errorf("got bank 0x%02X, expected 0x01", bankN)
+			}
+			if b := cart.Load(0xA000); b != 0xFF {
+				t.Errorf("got RAM enabled, expected disabled")
+			}
+
+			cart.Store(0x0000, 0x0A)
+			if b := cart.Load(0xA000); b != tc.expected {
+				t.Errorf("got 0x%02X, expected 0x%02X", b, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package cartridge
+
+// Bit of the RAM bank register that maps the camera registers into
+// 0xA000-0xBFFF in place of the external RAM.
+const cameraRegistersSelect uint8 = 1 << 4
+
+// Camera register that starts a capture when bit 0 is set, and reads back
+// bit 0 set for as long as the capture is in progress.
+const cameraCapture = 0x00
+
+// Number of camera registers, mirrored across 0xA000-0xBFFF.
+const cameraRegisters = 0x80
+
+// Location and size of the captured image in RAM bank 0.
+const (
+	cameraImage     = 0x0100
+	cameraImageSize = 0x0E00
+)
+
+// pocketCamera is the memory bank controller of the Game Boy Camera. It
+// supports up to 1 MiB of ROM and 128 KiB of external RAM, and drives the
+// image sensor through registers that can be mapped into 0xA000-0xBFFF in
+// place of the external RAM.
+//
+// No image sensor is emulated: captures complete immediately and produce a
+// blank image.
+type pocketCamera struct {
+	rom []uint8
+	ram []uint8
+
+	ramEnabled bool
+	romBank    uint8
+	ramBank    uint8
+	registers  [cameraRegisters]uint8
+}
+
+func newPocketCamera(rom, ram []uint8) *pocketCamera {
+	return &pocketCamera{
+		rom:     rom,
+		ram:     ram,
+		romBank: 1,
+	}
+}
+
+// Returns whether the camera registers are mapped into 0xA000-0xBFFF.
+func (c *pocketCamera) registersSelected() bool {
+	return c.ramBank&cameraRegistersSelect != 0
+}
+
+// Load returns the contents of the cartridge at the provided address. The
+// external RAM can be read even when it is not enabled. Only the capture
+// register of the camera can be read back; the rest read as 0x00.
+func (c *pocketCamera) Load(addr uint16) uint8 {
+	switch {
+	case addr <= romBank0End:
+		return romByte(c.rom, 0, addr)
+	case addr <= romBankNEnd:
+		return romByte(c.rom, int(c.romBank), addr-romBankN)
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if c.registersSelected() {
+			if (addr-externalRAM)%cameraRegisters == cameraCapture {
+				return c.registers[cameraCapture]
+			}
+			return 0x00
+		}
+		if b := ramByte(c.ram, int(c.ramBank), addr-externalRAM); b != nil {
+			return *b
+		}
+	}
+
+	return 0xFF
+}
+
+// Store writes to the registers of the controller when addr falls in the ROM,
+// and saves the provided value into the external RAM or the selected camera
+// register otherwise.
+//
+// 0x0000-0x1FFF enables writing to the external RAM when the lower nibble is
+// 0xA.
+// 0x2000-0x3FFF selects the ROM bank.
+// 0x4000-0x5FFF selects the RAM bank through bits 0-3, or maps the camera
+// registers into 0xA000-0xBFFF when bit 4 is set.
+func (c *pocketCamera) Store(addr uint16, b uint8) bool {
+	switch {
+	case addr <= 0x1FFF:
+		c.ramEnabled = b&0x0F == 0x0A
+	case addr <= 0x3FFF:
+		c.romBank = b & 0x3F
+	case addr <= 0x5FFF:
+		c.ramBank = b & 0x1F
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if c.registersSelected() {
+			return c.storeRegister((addr-externalRAM)%cameraRegisters, b)
+		}
+		if p := ramByte(c.ram, int(c.ramBank), addr-externalRAM); c.ramEnabled && p != nil {
+			return storeRAM(p, b)
+		}
+	}
+
+	return false
+}
+
+// Saves the provided value into the provided camera register, capturing a
+// blank image if a capture is started. Returns whether the image in the
+// external RAM was overwritten.
+func (c *pocketCamera) storeRegister(reg uint16, b uint8) bool {
+	if reg != cameraCapture || b&0x01 == 0 {
+		c.registers[reg] = b
+		return false
+	}
+
+	c.registers[cameraCapture] = b &^ 0x01
+	if len(c.ram) < cameraImage+cameraImageSize {
+		return false
+	}
+	for i := range c.ram[cameraImage : cameraImage+cameraImageSize] {
+		c.ram[cameraImage+i] = 0x00
+	}
+
+	return true
+}
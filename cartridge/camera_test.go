@@ -0,0 +1,41 @@
+package cartridge
+
+import "testing"
+
+func TestPocketCamera(t *testing.T) {
+	cart, err := New(NewROM(0xFC, 0x100000, 0x04))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	// Unlike most controllers, bank 0 can be mapped into 0x4000-0x7FFF.
+	for _, bank := range []uint8{0x00, 0x3F} {
+		cart.Store(0x2000, bank)
+		if bankN := cart.Load(0x4000); bankN != bank {
+			t.Errorf("got bank 0x%02X, expected 0x%02X", bankN, bank)
+		}
+	}
+
+	// The external RAM can be read, but not written to, while disabled.
+	cart.Store(0xA100, 0x12)
+	if b := cart.Load(0xA100); b != 0x00 {
+		t.Errorf("disabled: got 0x%02X, expected 0x00", b)
+	}
+	cart.Store(0x0000, 0x0A)
+	cart.Store(0xA100, 0x12)
+	cart.Store(0x0000, 0x00)
+	if b := cart.Load(0xA100); b != 0x12 {
+		t.Errorf("got 0x%02X, expected 0x12", b)
+	}
+
+	// A capture completes immediately and blanks the image.
+	cart.Store(0x4000, cameraRegistersSelect)
+	cart.Store(0xA000, 0x03)
+	if b := cart.Load(0xA000); b != 0x02 {
+		t.Errorf("capture: got 0x%02X, expected 0x02", b)
+	}
+	cart.Store(0x4000, 0x00)
+	if b := cart.Load(0xA100); b != 0x00 {
+		t.Errorf("image: got 0x%02X, expected 0x00", b)
+	}
+}
@@ -0,0 +1,412 @@
+package cartridge
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Controller is the type for our memory bank controller enumeration.
+type Controller uint8
+
+// Enumerates the memory bank controllers found in Game Boy cartridges.
+const (
+	ControllerNone Controller = iota
+	ControllerMBC1
+	ControllerMBC2
+	ControllerMMM01
+	ControllerMBC3
+	ControllerMBC5
+	ControllerMBC6
+	ControllerMBC7
+	ControllerPocketCamera
+	ControllerTAMA5
+	ControllerHuC3
+	ControllerHuC1
+)
+
+func (controller Controller) String() string {
+	switch controller {
+	case ControllerNone:
+		return "ROM"
+	case ControllerMBC1:
+		return "MBC1"
+	case ControllerMBC2:
+		return "MBC2"
+	case ControllerMMM01:
+		return "MMM01"
+	case ControllerMBC3:
+		return "MBC3"
+	case ControllerMBC5:
+		return "MBC5"
+	case ControllerMBC6:
+		return "MBC6"
+	case ControllerMBC7:
+		return "MBC7"
+	case ControllerPocketCamera:
+		return "POCKET CAMERA"
+	case ControllerTAMA5:
+		return "BANDAI TAMA5"
+	case ControllerHuC3:
+		return "HuC3"
+	case ControllerHuC1:
+		return "HuC1"
+	default:
+		return "?"
+	}
+}
+
+// Type is the cartridge type byte stored at 0x0147 of the cartridge header.
+// It describes the memory bank controller and any additional hardware found
+// in the cartridge.
+type Type uint8
+
+// Hardware describes the hardware found in a cartridge of a particular type.
+type Hardware struct {
+	Controller Controller
+	RAM        bool
+	Battery    bool
+	Timer      bool
+	Rumble     bool
+	Sensor     bool
+}
+
+var hardware = map[Type]Hardware{
+	0x00: {Controller: ControllerNone},
+	0x01: {Controller: ControllerMBC1},
+	0x02: {Controller: ControllerMBC1, RAM: true},
+	0x03: {Controller: ControllerMBC1, RAM: true, Battery: true},
+	0x05: {Controller: ControllerMBC2},
+	0x06: {Controller: ControllerMBC2, Battery: true},
+	0x08: {Controller: ControllerNone, RAM: true},
+	0x09: {Controller: ControllerNone, RAM: true, Battery: true},
+	0x0B: {Controller: ControllerMMM01},
+	0x0C: {Controller: ControllerMMM01, RAM: true},
+	0x0D: {Controller: ControllerMMM01, RAM: true, Battery: true},
+	0x0F: {Controller: ControllerMBC3, Timer: true, Battery: true},
+	0x10: {Controller: ControllerMBC3, Timer: true, RAM: true, Battery: true},
+	0x11: {Controller: ControllerMBC3},
+	0x12: {Controller: ControllerMBC3, RAM: true},
+	0x13: {Controller: ControllerMBC3, RAM: true, Battery: true},
+	0x19: {Controller: ControllerMBC5},
+	0x1A: {Controller: ControllerMBC5, RAM: true},
+	0x1B: {Controller: ControllerMBC5, RAM: true, Battery: true},
+	0x1C: {Controller: ControllerMBC5, Rumble: true},
+	0x1D: {Controller: ControllerMBC5, Rumble: true, RAM: true},
+	0x1E: {Controller: ControllerMBC5, Rumble: true, RAM: true, Battery: true},
+	0x20: {Controller: ControllerMBC6},
+	0x22: {Controller: ControllerMBC7, Sensor: true, Rumble: true, RAM: true, Battery: true},
+	0xFC: {Controller: ControllerPocketCamera, Battery: true},
+	0xFD: {Controller: ControllerTAMA5},
+	0xFE: {Controller: ControllerHuC3, Battery: true},
+	0xFF: {Controller: ControllerHuC1, RAM: true, Battery: true},
+}
+
+// Hardware returns the hardware found in cartridges of type t, and whether t
+// is a known cartridge type.
+func (t Type) Hardware() (Hardware, bool) {
+	h, ok := hardware[t]
+	return h, ok
+}
+
+func (t Type) String() string {
+	h, ok := t.Hardware()
+	if !ok {
+		return "?"
+	}
+
+	s := h.Controller.String()
+	if h.Timer {
+		s += "+TIMER"
+	}
+	if h.Sensor {
+		s += "+SENSOR"
+	}
+	if h.Rumble {
+		s += "+RUMBLE"
+	}
+	if h.RAM {
+		s += "+RAM"
+	}
+	if h.Battery {
+		s += "+BATTERY"
+	}
+
+	return s
+}
+
+// BankController is the interface that wraps the functionality that must be
+// provided by the memory bank controller of a cartridge. The controller sees
+// every access to the cartridge ROM (0x0000-0x7FFF) and to the external RAM
+// (0xA000-0xBFFF).
+//
+// Store returns whether the write changed state kept alive by the battery of
+// the cartridge, such as the contents of the external RAM or the registers of
+// a real-time clock.
+type BankController interface {
+	Load(addr uint16) uint8
+	Store(addr uint16, b uint8) bool
+}
+
+// UnsupportedTypeError is returned when a cartridge uses hardware that is not
+// emulated.
+type UnsupportedTypeError struct {
+	Type Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("unsupported cartridge type 0x%02X (%s)", uint8(e.Type), e.Type)
+}
+
+// Cartridge is a Game Boy cartridge, made up of a ROM image, the header
+// describing it, and the memory bank controller that maps it into memory.
+type Cartridge struct {
+	BankController
+	Header *Header
+
+	image []uint8
+	hw    Hardware
+	opts  options
+	// newController returns the memory bank controller of the cartridge in
+	// its power-up state.
+	newController func() BankController
+
+	ram       []uint8
+	rtc       *rtc
+	huc3Clock *huc3Clock
+	battery   bool
+
+	// mu guards the external RAM and the real-time clock against being saved
+	// while the game writes to them.
+	mu    sync.Mutex
+	dirty bool
+}
+
+type options struct {
+	clock                Clock
+	onRumble             func(on bool)
+	ignoreGlobalChecksum bool
+}
+
+// Option configures a cartridge created by New.
+type Option func(*options)
+
+// WithClock makes the real-time clock of the cartridge, if it has one, tell
+// the time using the provided clock instead of the system clock.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithRumbleHandler makes rumble cartridges call the provided function every
+// time their motor is turned on or off.
+func WithRumbleHandler(onRumble func(on bool)) Option {
+	return func(o *options) {
+		o.onRumble = onRumble
+	}
+}
+
+// IgnoreGlobalChecksum makes New accept ROM images whose global checksum does
+// not match, as the boot ROM does. ROM hacks seldom update it.
+func IgnoreGlobalChecksum() Option {
+	return func(o *options) {
+		o.ignoreGlobalChecksum = true
+	}
+}
+
+// New validates the header of the provided ROM image and returns a cartridge
+// using the memory bank controller the header asks for.
+func New(rom []uint8, opts ...Option) (*Cartridge, error) {
+	o := options{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	image := make([]uint8, len(rom))
+	copy(image, rom)
+
+	header, err := verifiedHeader(image, o)
+	if err != nil {
+		return nil, err
+	}
+
+	hw, _ := header.Type.Hardware()
+	c := &Cartridge{
+		Header:  header,
+		image:   image,
+		hw:      hw,
+		opts:    o,
+		ram:     newRAM(header, hw),
+		battery: hw.Battery,
+	}
+	switch {
+	case hw.Controller == ControllerMBC3 && hw.Timer:
+		c.rtc = newRTC(o.clock)
+	case hw.Controller == ControllerHuC3:
+		c.huc3Clock = newHuC3Clock(o.clock)
+	}
+
+	if c.newController, err = c.controller(); err != nil {
+		return nil, err
+	}
+	c.BankController = c.newController()
+
+	return c, nil
+}
+
+// Returns the header describing the provided ROM image, once verified. The
+// header at the start of MMM01 images belongs to the first game, while the one
+// describing the multicart itself is found in the menu, which is mapped on
+// power up. No header holds the global checksum of a whole multicart, so it is
+// left unverified, as the hardware itself does.
+func verifiedHeader(rom []uint8, o options) (*Header, error) {
+	if menu := mmm01Header(rom); menu != nil {
+		if len(rom) < menu.ROMSize {
+			return nil, ErrTruncatedROM
+		}
+		return menu, nil
+	}
+
+	header, err := ParseHeader(rom)
+	var checksumErr *ChecksumError
+	if errors.As(err, &checksumErr) && checksumErr.Global && o.ignoreGlobalChecksum {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// Returns a function creating the memory bank controller of the cartridge in
+// its power-up state, attached to the ROM image, the external RAM and the
+// real-time clock of the cartridge. The clock is looked up on every call, as
+// it is replaced when the cartridge is reset.
+func (c *Cartridge) controller() (func() BankController, error) {
+	image, ram := c.image, c.ram
+
+	switch c.hw.Controller {
+	case ControllerNone:
+		return func() BankController { return newROMOnly(image, ram) }, nil
+	case ControllerMBC1:
+		multicart := isMBC1M(image)
+		return func() BankController { return newMBC1(image, ram, multicart) }, nil
+	case ControllerMBC2:
+		return func() BankController { return newMBC2(image, ram) }, nil
+	case ControllerMMM01:
+		return func() BankController { return newMMM01(image, ram) }, nil
+	case ControllerMBC3:
+		return func() BankController { return newMBC3(image, ram, c.rtc) }, nil
+	case ControllerMBC5:
+		return func() BankController { return newMBC5(image, ram, c.hw.Rumble, c.opts.onRumble) }, nil
+	case ControllerPocketCamera:
+		return func() BankController { return newPocketCamera(image, ram) }, nil
+	case ControllerHuC3:
+		return func() BankController { return newHuC3(image, ram, c.huc3Clock) }, nil
+	case ControllerHuC1:
+		return func() BankController { return newHuC1(image, ram) }, nil
+	}
+
+	return nil, &UnsupportedTypeError{Type: c.Header.Type}
+}
+
+// Reset puts the memory bank controller back in its power-up state. The
+// external RAM and the real-time clock are cleared, unless keepBatteryRAM is
+// true and they are kept alive by a battery.
+func (c *Cartridge) Reset(keepBatteryRAM bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !keepBatteryRAM || !c.battery {
+		for i := range c.ram {
+			c.ram[i] = 0
+		}
+		if c.rtc != nil {
+			c.rtc = newRTC(c.opts.clock)
+		}
+		if c.huc3Clock != nil {
+			c.huc3Clock = newHuC3Clock(c.opts.clock)
+		}
+	}
+
+	c.BankController = c.newController()
+}
+
+// Returns the external RAM of a cartridge, or nil if it has none. The MBC2
+// has its RAM built in, and the Pocket Camera and HuC3 always have RAM,
+// although their cartridge type does not say so.
+func newRAM(header *Header, hw Hardware) []uint8 {
+	switch {
+	case hw.Controller == ControllerMBC2:
+		return make([]uint8, mbc2RAMSize)
+	case header.RAMSize == 0:
+		return nil
+	case hw.RAM, hw.Controller == ControllerPocketCamera, hw.Controller == ControllerHuC3:
+		return make([]uint8, header.RAMSize)
+	default:
+		return nil
+	}
+}
+
+// Returns the header of the menu of an MMM01 multicart, found at the start of
+// the last 32 KiB of the image, or nil if the image does not hold one.
+func mmm01Header(rom []uint8) *Header {
+	if len(rom) <= mmm01MenuSize {
+		return nil
+	}
+
+	menu := rom[len(rom)-mmm01MenuSize:]
+	h, err := decodeHeader(menu)
+	if err != nil || HeaderChecksum(menu) != h.HeaderChecksum {
+		return nil
+	}
+
+	if hw, _ := h.Type.Hardware(); hw.Controller != ControllerMMM01 {
+		return nil
+	}
+
+	return h
+}
+
+// Cartridge memory regions, as seen by the bank controllers.
+const (
+	romBank0End    = 0x3FFF
+	romBankN       = 0x4000
+	romBankNEnd    = 0x7FFF
+	externalRAM    = 0xA000
+	externalRAMEnd = 0xBFFF
+)
+
+// Returns the byte at the provided offset of bank in rom. The bank number is
+// wrapped around the number of banks in the image, the same way the unused
+// high bits of the bank number are ignored by the hardware.
+func romByte(rom []uint8, bank int, offset uint16) uint8 {
+	banks := len(rom) / romBankSize
+	if banks == 0 {
+		return 0xFF
+	}
+
+	return rom[(bank%banks)*romBankSize+int(offset)]
+}
+
+// Returns a pointer to the byte at the provided offset of bank in ram, or nil
+// if the cartridge has no external RAM. The bank number is wrapped around the
+// number of banks in the RAM.
+func ramByte(ram []uint8, bank int, offset uint16) *uint8 {
+	if len(ram) == 0 {
+		return nil
+	}
+
+	return &ram[(bank*ramBankSize+int(offset))%len(ram)]
+}
+
+// Saves the provided value into the byte of external RAM p points to, and
+// returns whether its contents changed.
+func storeRAM(p *uint8, b uint8) bool {
+	changed := *p != b
+	*p = b
+
+	return changed
+}
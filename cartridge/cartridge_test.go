@@ -0,0 +1,36 @@
+package cartridge
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewUnsupported(t *testing.T) {
+	_, err := New(NewROM(0xFD, 0x8000, 0x00))
+
+	var uerr *UnsupportedTypeError
+	if !errors.As(err, &uerr) {
+		t.Fatalf("got %v, expected *UnsupportedTypeError", err)
+	}
+	if uerr.Type != 0xFD {
+		t.Errorf("got 0x%02X, expected 0xFD", uint8(uerr.Type))
+	}
+}
+
+func TestNewIgnoreGlobalChecksum(t *testing.T) {
+	rom := NewROM(0x00, 0x8000, 0x00)
+	rom[0x4000]++
+
+	if _, err := New(rom); err == nil {
+		t.Error("got nil, expected global checksum error")
+	}
+	if _, err := New(rom, IgnoreGlobalChecksum()); err != nil {
+		t.Errorf("got %v, expected nil", err)
+	}
+
+	// The header checksum is still verified.
+	rom[addrTitle]++
+	if _, err := New(rom, IgnoreGlobalChecksum()); err == nil {
+		t.Error("got nil, expected header checksum error")
+	}
+}
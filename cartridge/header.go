@@ -0,0 +1,317 @@
+package cartridge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrTruncatedHeader is returned when a ROM image is too small to contain
+	// a cartridge header.
+	ErrTruncatedHeader = errors.New("rom image too small to contain a cartridge header")
+
+	// ErrUnknownType is returned when the cartridge type byte does not match
+	// any known cartridge type.
+	ErrUnknownType = errors.New("unknown cartridge type")
+
+	// ErrUnknownROMSize is returned when the ROM size byte does not match any
+	// known ROM size.
+	ErrUnknownROMSize = errors.New("unknown rom size")
+
+	// ErrUnknownRAMSize is returned when the RAM size byte does not match any
+	// known RAM size.
+	ErrUnknownRAMSize = errors.New("unknown ram size")
+
+	// ErrTruncatedROM is returned when a ROM image is smaller than the ROM
+	// size reported by its header.
+	ErrTruncatedROM = errors.New("rom image smaller than the size reported by its header")
+)
+
+// Locations of the cartridge header fields within a ROM image.
+const (
+	addrTitle              = 0x0134
+	addrManufacturerCode   = 0x013F
+	addrCGBFlag            = 0x0143
+	addrNewLicenseeCode    = 0x0144
+	addrSGBFlag            = 0x0146
+	addrType               = 0x0147
+	addrROMSize            = 0x0148
+	addrRAMSize            = 0x0149
+	addrDestinationCode    = 0x014A
+	addrOldLicenseeCode    = 0x014B
+	addrVersion            = 0x014C
+	addrHeaderChecksum     = 0x014D
+	addrGlobalChecksum     = 0x014E
+	headerEnd              = 0x014F
+	oldLicenseeCodeUseNew  = 0x33
+	sgbFlagSupported       = 0x03
+	romBankSize            = 0x4000
+	ramBankSize            = 0x2000
+	headerChecksumRangeEnd = addrVersion
+)
+
+// CGBSupport describes whether a cartridge supports the Game Boy Color.
+type CGBSupport uint8
+
+// Enumerates the values of the CGB flag stored at 0x0143 of the cartridge
+// header.
+const (
+	CGBUnsupported CGBSupport = 0x00
+	CGBCompatible  CGBSupport = 0x80
+	CGBOnly        CGBSupport = 0xC0
+)
+
+func (cgb CGBSupport) String() string {
+	switch cgb {
+	case CGBCompatible:
+		return "compatible"
+	case CGBOnly:
+		return "only"
+	default:
+		return "unsupported"
+	}
+}
+
+// ChecksumError is returned when a checksum stored in the cartridge header
+// does not match the checksum computed from the ROM image.
+type ChecksumError struct {
+	Global   bool
+	Stored   uint16
+	Computed uint16
+}
+
+func (e *ChecksumError) Error() string {
+	if e.Global {
+		return fmt.Sprintf("global checksum mismatch: stored 0x%04X, computed 0x%04X", e.Stored, e.Computed)
+	}
+
+	return fmt.Sprintf("header checksum mismatch: stored 0x%02X, computed 0x%02X", e.Stored, e.Computed)
+}
+
+// Header is a decoded cartridge header, found at 0x0100-0x014F of every ROM
+// image.
+type Header struct {
+	Title            string
+	ManufacturerCode string
+	CGB              CGBSupport
+	SGB              bool
+	Type             Type
+	ROMSize          int
+	RAMSize          int
+	LicenseeCode     string
+	Destination      uint8
+	Version          uint8
+	HeaderChecksum   uint8
+	GlobalChecksum   uint16
+}
+
+// ParseHeader decodes the cartridge header of the provided ROM image and
+// verifies its header and global checksums. A *ChecksumError is returned if
+// either checksum does not match.
+func ParseHeader(rom []uint8) (*Header, error) {
+	h, err := decodeHeader(rom)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.verify(rom); err != nil {
+		return h, err
+	}
+
+	return h, nil
+}
+
+// Decodes the cartridge header of the provided ROM image, without verifying
+// it against the image.
+func decodeHeader(rom []uint8) (*Header, error) {
+	if len(rom) <= headerEnd {
+		return nil, ErrTruncatedHeader
+	}
+
+	h := &Header{
+		CGB:            CGBSupport(rom[addrCGBFlag] & 0xC0),
+		SGB:            rom[addrSGBFlag] == sgbFlagSupported,
+		Type:           Type(rom[addrType]),
+		Destination:    rom[addrDestinationCode],
+		Version:        rom[addrVersion],
+		HeaderChecksum: rom[addrHeaderChecksum],
+		GlobalChecksum: uint16(rom[addrGlobalChecksum])<<8 | uint16(rom[addrGlobalChecksum+1]),
+	}
+
+	// Cartridges released after the Game Boy Color shortened the title to
+	// make room for the manufacturer code and the CGB flag.
+	if h.CGB != CGBUnsupported {
+		h.Title = decodeString(rom[addrTitle:addrManufacturerCode])
+		h.ManufacturerCode = decodeString(rom[addrManufacturerCode:addrCGBFlag])
+	} else {
+		h.Title = decodeString(rom[addrTitle:addrNewLicenseeCode])
+	}
+
+	if old := rom[addrOldLicenseeCode]; old == oldLicenseeCodeUseNew {
+		h.LicenseeCode = decodeString(rom[addrNewLicenseeCode:addrSGBFlag])
+	} else {
+		h.LicenseeCode = fmt.Sprintf("%02X", old)
+	}
+
+	if _, ok := h.Type.Hardware(); !ok {
+		return nil, ErrUnknownType
+	}
+
+	var ok bool
+	if h.ROMSize, ok = romSize(rom[addrROMSize]); !ok {
+		return nil, ErrUnknownROMSize
+	}
+	if h.RAMSize, ok = ramSize(rom[addrRAMSize]); !ok {
+		return nil, ErrUnknownRAMSize
+	}
+
+	return h, nil
+}
+
+// Verifies the ROM image against the header.
+func (h *Header) verify(rom []uint8) error {
+	if len(rom) < h.ROMSize {
+		return ErrTruncatedROM
+	}
+
+	if computed := HeaderChecksum(rom); computed != h.HeaderChecksum {
+		return &ChecksumError{Stored: uint16(h.HeaderChecksum), Computed: uint16(computed)}
+	}
+
+	if computed := GlobalChecksum(rom); computed != h.GlobalChecksum {
+		return &ChecksumError{Global: true, Stored: h.GlobalChecksum, Computed: computed}
+	}
+
+	return nil
+}
+
+// ROMBanks returns the number of 16 KiB ROM banks in the cartridge.
+func (h *Header) ROMBanks() int {
+	return h.ROMSize / romBankSize
+}
+
+// RAMBanks returns the number of 8 KiB external RAM banks in the cartridge.
+func (h *Header) RAMBanks() int {
+	return h.RAMSize / ramBankSize
+}
+
+func (h *Header) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "title:             %s\n", h.Title)
+	if h.ManufacturerCode != "" {
+		fmt.Fprintf(&sb, "manufacturer code: %s\n", h.ManufacturerCode)
+	}
+	fmt.Fprintf(&sb, "licensee code:     %s\n", h.LicenseeCode)
+	fmt.Fprintf(&sb, "cgb:               %s\n", h.CGB)
+	fmt.Fprintf(&sb, "sgb:               %t\n", h.SGB)
+	fmt.Fprintf(&sb, "type:              0x%02X %s\n", uint8(h.Type), h.Type)
+	fmt.Fprintf(&sb, "rom size:          %d KiB (%d banks)\n", h.ROMSize/1024, h.ROMBanks())
+	fmt.Fprintf(&sb, "ram size:          %d KiB (%d banks)\n", h.RAMSize/1024, h.RAMBanks())
+	fmt.Fprintf(&sb, "destination:       0x%02X\n", h.Destination)
+	fmt.Fprintf(&sb, "version:           0x%02X\n", h.Version)
+	fmt.Fprintf(&sb, "header checksum:   0x%02X\n", h.HeaderChecksum)
+	fmt.Fprintf(&sb, "global checksum:   0x%04X", h.GlobalChecksum)
+
+	return sb.String()
+}
+
+// HeaderChecksum returns the checksum of the cartridge header bytes at
+// 0x0134-0x014C, computed the same way as the boot ROM does.
+func HeaderChecksum(rom []uint8) (checksum uint8) {
+	for _, b := range rom[addrTitle : headerChecksumRangeEnd+1] {
+		checksum = checksum - b - 1
+	}
+
+	return checksum
+}
+
+// GlobalChecksum returns the sum of every byte in the ROM image, excluding the
+// two bytes of the global checksum itself.
+func GlobalChecksum(rom []uint8) (checksum uint16) {
+	for i, b := range rom {
+		if i != addrGlobalChecksum && i != addrGlobalChecksum+1 {
+			checksum += uint16(b)
+		}
+	}
+
+	return checksum
+}
+
+// NewROM returns a ROM image of the provided size holding no program, with a
+// valid header describing a cartridge of type t and the provided RAM size
+// byte. Every ROM bank is filled with its own number.
+func NewROM(t Type, size int, ramSize uint8) []uint8 {
+	rom := make([]uint8, size)
+	for i := romBankSize; i < size; i++ {
+		rom[i] = uint8(i / romBankSize)
+	}
+	copy(rom[addrTitle:], "TESTROM")
+	rom[addrType] = uint8(t)
+	for b := uint8(0); b <= 0x08; b++ {
+		if s, _ := romSize(b); s == size {
+			rom[addrROMSize] = b
+		}
+	}
+	rom[addrRAMSize] = ramSize
+	rom[addrOldLicenseeCode] = oldLicenseeCodeUseNew
+	copy(rom[addrNewLicenseeCode:], "01")
+	FixChecksums(rom)
+
+	return rom
+}
+
+// FixChecksums recomputes the header and global checksums of the provided ROM
+// image, so that it passes verification once modified.
+func FixChecksums(rom []uint8) {
+	rom[addrHeaderChecksum] = HeaderChecksum(rom)
+	global := GlobalChecksum(rom)
+	rom[addrGlobalChecksum] = uint8(global >> 8)
+	rom[addrGlobalChecksum+1] = uint8(global)
+}
+
+// Returns the ROM size in bytes corresponding to the provided ROM size byte.
+func romSize(b uint8) (int, bool) {
+	switch {
+	case b <= 0x08:
+		return 0x8000 << b, true
+	case b == 0x52:
+		return 72 * romBankSize, true
+	case b == 0x53:
+		return 80 * romBankSize, true
+	case b == 0x54:
+		return 96 * romBankSize, true
+	default:
+		return 0, false
+	}
+}
+
+// Returns the RAM size in bytes corresponding to the provided RAM size byte.
+func ramSize(b uint8) (int, bool) {
+	switch b {
+	case 0x00:
+		return 0, true
+	case 0x01:
+		return 0x0800, true
+	case 0x02:
+		return 1 * ramBankSize, true
+	case 0x03:
+		return 4 * ramBankSize, true
+	case 0x04:
+		return 16 * ramBankSize, true
+	case 0x05:
+		return 8 * ramBankSize, true
+	default:
+		return 0, false
+	}
+}
+
+// Decodes a NUL-padded ASCII string.
+func decodeString(b []uint8) string {
+	if i := strings.IndexByte(string(b), 0); i >= 0 {
+		b = b[:i]
+	}
+
+	return strings.TrimSpace(string(b))
+}
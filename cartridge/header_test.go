@@ -0,0 +1,121 @@
+package cartridge
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseHeader(t *testing.T) {
+	rom := NewROM(0x13, 0x10000, 0x03)
+	rom[addrCGBFlag] = uint8(CGBCompatible)
+	copy(rom[addrManufacturerCode:], "ABCD")
+	rom[addrSGBFlag] = sgbFlagSupported
+	rom[addrVersion] = 0x01
+	FixChecksums(rom)
+
+	h, err := ParseHeader(rom)
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	var testCases = []struct {
+		field string
+		out   interface{}
+		exp   interface{}
+	}{
+		{"Title", h.Title, "TESTROM"},
+		{"ManufacturerCode", h.ManufacturerCode, "ABCD"},
+		{"CGB", h.CGB, CGBCompatible},
+		{"SGB", h.SGB, true},
+		{"Type", h.Type, Type(0x13)},
+		{"ROMSize", h.ROMSize, 0x10000},
+		{"RAMSize", h.RAMSize, 0x8000},
+		{"LicenseeCode", h.LicenseeCode, "01"},
+		{"Version", h.Version, uint8(0x01)},
+		{"ROMBanks", h.ROMBanks(), 4},
+		{"RAMBanks", h.RAMBanks(), 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.field, func(t *testing.T) {
+			if tc.out != tc.exp {
+				t.Errorf("got %v, expected %v", tc.out, tc.exp)
+			}
+		})
+	}
+}
+
+func TestParseHeaderErrors(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		mutate func(rom []uint8) []uint8
+		err    error
+	}{
+		{"truncated header", func(rom []uint8) []uint8 { return rom[:headerEnd] }, ErrTruncatedHeader},
+		{"truncated rom", func(rom []uint8) []uint8 { return rom[:0x4000] }, ErrTruncatedROM},
+		{"unknown type", func(rom []uint8) []uint8 { rom[addrType] = 0x04; return rom }, ErrUnknownType},
+		{"unknown rom size", func(rom []uint8) []uint8 { rom[addrROMSize] = 0x09; return rom }, ErrUnknownROMSize},
+		{"unknown ram size", func(rom []uint8) []uint8 { rom[addrRAMSize] = 0x06; return rom }, ErrUnknownRAMSize},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseHeader(tc.mutate(NewROM(0x00, 0x8000, 0x00)))
+			if err != tc.err {
+				t.Errorf("got %v, expected %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestParseHeaderChecksums(t *testing.T) {
+	var testCases = []struct {
+		address uint16
+		global  bool
+	}{
+		{addrTitle, false},
+		{addrVersion, false},
+		{headerEnd + 1, true},
+		{0x7FFF, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("address=0x%04X", tc.address), func(t *testing.T) {
+			rom := NewROM(0x00, 0x8000, 0x00)
+			rom[tc.address]++
+
+			_, err := ParseHeader(rom)
+
+			var cerr *ChecksumError
+			if !errors.As(err, &cerr) {
+				t.Fatalf("got %v, expected *ChecksumError", err)
+			}
+			if cerr.Global != tc.global {
+				t.Errorf("got global=%t, expected global=%t", cerr.Global, tc.global)
+			}
+		})
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	var testCases = []struct {
+		t   Type
+		out string
+	}{
+		{0x00, "ROM"},
+		{0x03, "MBC1+RAM+BATTERY"},
+		{0x10, "MBC3+TIMER+RAM+BATTERY"},
+		{0x1E, "MBC5+RUMBLE+RAM+BATTERY"},
+		{0x22, "MBC7+SENSOR+RUMBLE+RAM+BATTERY"},
+		{0x04, "?"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("type=0x%02X", uint8(tc.t)), func(t *testing.T) {
+			if out := tc.t.String(); out != tc.out {
+				t.Errorf("got %s, expected %s", out, tc.out)
+			}
+		})
+	}
+}
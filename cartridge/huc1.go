@@ -0,0 +1,82 @@
+package cartridge
+
+// Values that select what is mapped into 0xA000-0xBFFF of HuC1 and HuC3
+// cartridges.
+const (
+	hucModeIR uint8 = 0x0E
+
+	// Value read from the infrared receiver when it sees no light.
+	irNoLight uint8 = 0xC0
+)
+
+// huc1 is the HuC1 memory bank controller made by Hudson Soft. It works like
+// an MBC1 with a 6-bit ROM bank register, and has an infrared transceiver
+// that can be mapped into 0xA000-0xBFFF in place of the external RAM.
+type huc1 struct {
+	rom []uint8
+	ram []uint8
+
+	irMode  bool
+	irLED   bool
+	romBank uint8
+	ramBank uint8
+}
+
+func newHuC1(rom, ram []uint8) *huc1 {
+	return &huc1{
+		rom:     rom,
+		ram:     ram,
+		romBank: 1,
+	}
+}
+
+// Load returns the contents of the cartridge at the provided address. With
+// the infrared transceiver mapped in, the receiver never sees any light.
+func (c *huc1) Load(addr uint16) uint8 {
+	switch {
+	case addr <= romBank0End:
+		return romByte(c.rom, 0, addr)
+	case addr <= romBankNEnd:
+		return romByte(c.rom, int(c.romBank), addr-romBankN)
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if c.irMode {
+			return irNoLight
+		}
+		if b := ramByte(c.ram, int(c.ramBank), addr-externalRAM); b != nil {
+			return *b
+		}
+	}
+
+	return 0xFF
+}
+
+// Store writes to the registers of the controller when addr falls in the ROM,
+// and saves the provided value into the external RAM otherwise.
+//
+// 0x0000-0x1FFF maps the infrared transceiver into 0xA000-0xBFFF when 0x0E is
+// written, and the external RAM otherwise.
+// 0x2000-0x3FFF selects the ROM bank. Writing 0 selects bank 1 instead.
+// 0x4000-0x5FFF selects the RAM bank.
+func (c *huc1) Store(addr uint16, b uint8) bool {
+	switch {
+	case addr <= 0x1FFF:
+		c.irMode = b == hucModeIR
+	case addr <= 0x3FFF:
+		c.romBank = b & 0x3F
+		if c.romBank == 0 {
+			c.romBank = 1
+		}
+	case addr <= 0x5FFF:
+		c.ramBank = b & 0x03
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if c.irMode {
+			c.irLED = b&0x01 != 0
+			return false
+		}
+		if p := ramByte(c.ram, int(c.ramBank), addr-externalRAM); p != nil {
+			return storeRAM(p, b)
+		}
+	}
+
+	return false
+}
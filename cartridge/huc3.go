@@ -0,0 +1,240 @@
+package cartridge
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// Values that select what is mapped into 0xA000-0xBFFF of HuC3 cartridges.
+const (
+	huc3ModeRAMReadOnly uint8 = 0x00
+	huc3ModeRAM         uint8 = 0x0A
+	huc3ModeCommand     uint8 = 0x0B
+	huc3ModeResponse    uint8 = 0x0C
+	huc3ModeSemaphore   uint8 = 0x0D
+)
+
+// Commands understood by the HuC3 real-time clock. The command is held in
+// bits 4-6 of the value written in command mode, and its argument in bits
+// 0-3.
+const (
+	huc3CommandRead     uint8 = 0x1
+	huc3CommandWrite    uint8 = 0x3
+	huc3CommandAddrLow  uint8 = 0x4
+	huc3CommandAddrHigh uint8 = 0x5
+	huc3CommandExtended uint8 = 0x6
+)
+
+// Arguments of the extended command.
+const (
+	huc3ExtendedLatch  uint8 = 0x0
+	huc3ExtendedSet    uint8 = 0x1
+	huc3ExtendedStatus uint8 = 0x2
+)
+
+// Locations in the HuC3 clock memory where the latched time is held. Both the
+// minute of the day and the day counter are 12 bits wide, stored as three
+// nibbles starting from the least significant one.
+const (
+	huc3MemoryMinutes = 0x00
+	huc3MemoryDays    = 0x03
+	huc3MinutesPerDay = 24 * 60
+)
+
+// huc3 is the HuC3 memory bank controller made by Hudson Soft. On top of ROM
+// and RAM banking, it has a real-time clock driven through a command
+// interface, and an infrared transceiver.
+type huc3 struct {
+	rom []uint8
+	ram []uint8
+	rtc *huc3Clock
+
+	mode    uint8
+	romBank uint8
+	ramBank uint8
+	irLED   bool
+
+	// address is the location in the clock memory the next read or write
+	// command accesses.
+	address uint8
+	// command and response are the last command executed and the nibble it
+	// produced.
+	command  uint8
+	response uint8
+}
+
+func newHuC3(rom, ram []uint8, rtc *huc3Clock) *huc3 {
+	return &huc3{
+		rom:     rom,
+		ram:     ram,
+		rtc:     rtc,
+		romBank: 1,
+	}
+}
+
+// Load returns the contents of the cartridge at the provided address.
+func (c *huc3) Load(addr uint16) uint8 {
+	switch {
+	case addr <= romBank0End:
+		return romByte(c.rom, 0, addr)
+	case addr <= romBankNEnd:
+		return romByte(c.rom, int(c.romBank), addr-romBankN)
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		switch c.mode {
+		case huc3ModeRAMReadOnly, huc3ModeRAM:
+			if b := ramByte(c.ram, int(c.ramBank), addr-externalRAM); b != nil {
+				return *b
+			}
+		case huc3ModeResponse:
+			return 0x80 | c.command<<4 | c.response
+		case huc3ModeSemaphore:
+			// Commands complete immediately, so the clock is always ready.
+			return 0x01
+		case hucModeIR:
+			return irNoLight
+		}
+	}
+
+	return 0xFF
+}
+
+// Store writes to the registers of the controller when addr falls in the ROM,
+// and to whatever is mapped into 0xA000-0xBFFF otherwise.
+//
+// 0x0000-0x1FFF selects what is mapped into 0xA000-0xBFFF.
+// 0x2000-0x3FFF selects the ROM bank. Writing 0 selects bank 1 instead.
+// 0x4000-0x5FFF selects the RAM bank.
+func (c *huc3) Store(addr uint16, b uint8) bool {
+	switch {
+	case addr <= 0x1FFF:
+		c.mode = b & 0x0F
+	case addr <= 0x3FFF:
+		c.romBank = b & 0x7F
+		if c.romBank == 0 {
+			c.romBank = 1
+		}
+	case addr <= 0x5FFF:
+		c.ramBank = b & 0x03
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		switch c.mode {
+		case huc3ModeRAM:
+			if p := ramByte(c.ram, int(c.ramBank), addr-externalRAM); p != nil {
+				return storeRAM(p, b)
+			}
+		case huc3ModeCommand:
+			return c.execute(b>>4&0x07, b&0x0F)
+		case hucModeIR:
+			c.irLED = b&0x01 != 0
+		}
+	}
+
+	return false
+}
+
+// Executes the provided clock command, and returns whether it changed the
+// state of the clock.
+func (c *huc3) execute(command, arg uint8) bool {
+	c.command = command
+
+	switch command {
+	case huc3CommandRead:
+		c.response = c.rtc.memory[c.address]
+		c.address++
+	case huc3CommandWrite:
+		c.rtc.memory[c.address] = arg
+		c.address++
+		return true
+	case huc3CommandAddrLow:
+		c.address = c.address&0xF0 | arg
+	case huc3CommandAddrHigh:
+		c.address = arg<<4 | c.address&0x0F
+	case huc3CommandExtended:
+		switch arg {
+		case huc3ExtendedLatch:
+			c.rtc.latch()
+			return true
+		case huc3ExtendedSet:
+			c.rtc.set()
+			return true
+		case huc3ExtendedStatus:
+			c.response = 0x01
+		}
+	}
+
+	return false
+}
+
+// huc3Clock is the real-time clock of HuC3 cartridges. It lives on the
+// cartridge rather than the controller, so that it keeps counting across
+// resets while the battery holds, and is saved along with the external RAM.
+type huc3Clock struct {
+	clock Clock
+	// base is the point in time the clock counts minutes and days from.
+	base time.Time
+	// memory is the 256-nibble memory of the clock, accessed through the
+	// command interface.
+	memory [256]uint8
+}
+
+func newHuC3Clock(clock Clock) *huc3Clock {
+	return &huc3Clock{clock: clock, base: clock.Now()}
+}
+
+// Copies the time elapsed since base into the clock memory.
+func (r *huc3Clock) latch() {
+	minutes := int(r.clock.Now().Sub(r.base) / time.Minute)
+	r.storeNibbles(huc3MemoryMinutes, minutes%huc3MinutesPerDay)
+	r.storeNibbles(huc3MemoryDays, minutes/huc3MinutesPerDay)
+}
+
+// Moves base so that the time elapsed since is the one held in the clock
+// memory.
+func (r *huc3Clock) set() {
+	minutes := r.loadNibbles(huc3MemoryMinutes) + r.loadNibbles(huc3MemoryDays)*huc3MinutesPerDay
+	r.base = r.clock.Now().Add(-time.Duration(minutes) * time.Minute)
+}
+
+// Stores the lower 12 bits of the provided value into three nibbles of the
+// clock memory, starting from addr.
+func (r *huc3Clock) storeNibbles(addr uint8, val int) {
+	for i := uint8(0); i < 3; i++ {
+		r.memory[addr+i] = uint8(val>>(4*i)) & 0x0F
+	}
+}
+
+// Returns the 12-bit value held in three nibbles of the clock memory,
+// starting from addr.
+func (r *huc3Clock) loadNibbles(addr uint8) (val int) {
+	for i := uint8(0); i < 3; i++ {
+		val |= int(r.memory[addr+i]&0x0F) << (4 * i)
+	}
+
+	return val
+}
+
+// Size of the clock footer appended to the save files of HuC3 cartridges. The
+// footer holds base as a 64-bit Unix timestamp, followed by the clock memory
+// packed two nibbles to a byte, low nibble first.
+const huc3FooterSize = 8 + len(huc3Clock{}.memory)/2
+
+// Returns the state of the clock encoded as a save file footer.
+func (r *huc3Clock) marshal() []uint8 {
+	b := make([]uint8, huc3FooterSize)
+	binary.LittleEndian.PutUint64(b, uint64(r.base.Unix()))
+	for i := 0; i < len(r.memory); i += 2 {
+		b[8+i/2] = r.memory[i]&0x0F | r.memory[i+1]<<4
+	}
+
+	return b
+}
+
+// Restores the state of the clock from the provided save file footer. As base
+// is an absolute point in time, the time passed since the footer was saved is
+// accounted for.
+func (r *huc3Clock) unmarshal(b []uint8) {
+	r.base = time.Unix(int64(binary.LittleEndian.Uint64(b)), 0)
+	for i := 0; i < len(r.memory); i += 2 {
+		r.memory[i] = b[8+i/2] & 0x0F
+		r.memory[i+1] = b[8+i/2] >> 4
+	}
+}
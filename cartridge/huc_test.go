@@ -0,0 +1,141 @@
+package cartridge
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestHuC1(t *testing.T) {
+	cart, err := New(NewROM(0xFF, 0x100000, 0x03))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	cart.Store(0x2000, 0x00)
+	if bankN := cart.Load(0x4000); bankN != 0x01 {
+		t.Errorf("got bank 0x%02X, expected 0x01", bankN)
+	}
+	cart.Store(0x2000, 0x3F)
+	if bankN := cart.Load(0x4000); bankN != 0x3F {
+		t.Errorf("got bank 0x%02X, expected 0x3F", bankN)
+	}
+
+	cart.Store(0x4000, 0x02)
+	cart.Store(0xA000, 0x12)
+	if b := cart.Load(0xA000); b != 0x12 {
+		t.Errorf("got 0x%02X, expected 0x12", b)
+	}
+
+	cart.Store(0x0000, hucModeIR)
+	if b := cart.Load(0xA000); b != irNoLight {
+		t.Errorf("IR: got 0x%02X, expected 0x%02X", b, irNoLight)
+	}
+
+	cart.Store(0x0000, 0x0A)
+	if b := cart.Load(0xA000); b != 0x12 {
+		t.Errorf("got 0x%02X, expected 0x12", b)
+	}
+}
+
+// Runs the provided HuC3 clock command and returns the response.
+func huc3Command(cart *Cartridge, command, arg uint8) uint8 {
+	cart.Store(0x0000, huc3ModeCommand)
+	cart.Store(0xA000, command<<4|arg)
+	cart.Store(0x0000, huc3ModeResponse)
+
+	return cart.Load(0xA000) & 0x0F
+}
+
+// Latches the HuC3 clock and returns the minute of the day and the day
+// counter.
+func huc3Time(cart *Cartridge) (minutes, days int) {
+	huc3Command(cart, huc3CommandExtended, huc3ExtendedLatch)
+	huc3Command(cart, huc3CommandAddrLow, 0x0)
+	huc3Command(cart, huc3CommandAddrHigh, 0x0)
+	for i := 0; i < 3; i++ {
+		minutes |= int(huc3Command(cart, huc3CommandRead, 0)) << (4 * i)
+	}
+	for i := 0; i < 3; i++ {
+		days |= int(huc3Command(cart, huc3CommandRead, 0)) << (4 * i)
+	}
+
+	return minutes, days
+}
+
+func TestHuC3Clock(t *testing.T) {
+	clock := &fakeClock{}
+	cart, err := New(NewROM(0xFE, 0x100000, 0x03), WithClock(clock))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	clock.advance(3*24*time.Hour + 90*time.Minute + 30*time.Second)
+	if minutes, days := huc3Time(cart); minutes != 90 || days != 3 {
+		t.Errorf("got %d minutes and %d days, expected 90 minutes and 3 days", minutes, days)
+	}
+
+	// Set the clock to day 0x123, 0x0AB minutes.
+	huc3Command(cart, huc3CommandAddrLow, 0x0)
+	huc3Command(cart, huc3CommandAddrHigh, 0x0)
+	for _, n := range []uint8{0xB, 0xA, 0x0, 0x3, 0x2, 0x1} {
+		huc3Command(cart, huc3CommandWrite, n)
+	}
+	huc3Command(cart, huc3CommandExtended, huc3ExtendedSet)
+
+	clock.advance(time.Minute)
+	if minutes, days := huc3Time(cart); minutes != 0xAC || days != 0x123 {
+		t.Errorf("got %d minutes and %d days, expected %d minutes and %d days", minutes, days, 0xAC, 0x123)
+	}
+
+	cart.Store(0x0000, huc3ModeSemaphore)
+	if b := cart.Load(0xA000); b != 0x01 {
+		t.Errorf("semaphore: got 0x%02X, expected 0x01", b)
+	}
+}
+
+func TestHuC3Reset(t *testing.T) {
+	var testCases = []struct {
+		keep            bool
+		minutes, days   int
+		expectedMinutes int
+		expectedDays    int
+	}{
+		{true, 90, 3, 90, 3},
+		{false, 90, 3, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("keep=%t", tc.keep), func(t *testing.T) {
+			clock := &fakeClock{}
+			cart, err := New(NewROM(0xFE, 0x100000, 0x03), WithClock(clock))
+			if err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+
+			clock.advance(time.Duration(tc.days)*24*time.Hour + time.Duration(tc.minutes)*time.Minute)
+			cart.Reset(tc.keep)
+			if minutes, days := huc3Time(cart); minutes != tc.expectedMinutes || days != tc.expectedDays {
+				t.Errorf("got %d minutes and %d days, expected %d minutes and %d days", minutes, days, tc.expectedMinutes, tc.expectedDays)
+			}
+		})
+	}
+}
+
+func TestHuC3RAM(t *testing.T) {
+	cart, err := New(NewROM(0xFE, 0x100000, 0x03))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	cart.Store(0x0000, huc3ModeRAM)
+	cart.Store(0x4000, 0x03)
+	cart.Store(0xA000, 0x34)
+
+	// The RAM is read-only with mode 0x00.
+	cart.Store(0x0000, huc3ModeRAMReadOnly)
+	cart.Store(0xA000, 0x56)
+	if b := cart.Load(0xA000); b != 0x34 {
+		t.Errorf("got 0x%02X, expected 0x34", b)
+	}
+}
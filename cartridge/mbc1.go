@@ -0,0 +1,131 @@
+package cartridge
+
+import "bytes"
+
+// Location of the Nintendo logo in the cartridge header, used to detect the
+// games packed in MBC1M multicarts.
+const (
+	logoStart = 0x0104
+	logoEnd   = 0x0134
+)
+
+// mbc1 is the MBC1 memory bank controller. It supports up to 2 MiB of ROM and
+// 32 KiB of external RAM, although not both at the same time: the 2-bit
+// bank2 register provides either the upper bits of the ROM bank number, or
+// the RAM bank number.
+//
+// MBC1M multicarts wire the controller differently, with bank2 providing bits
+// 4-5 of the ROM bank number instead of bits 5-6.
+type mbc1 struct {
+	rom []uint8
+	ram []uint8
+
+	ramEnabled bool
+	bank1      uint8
+	bank2      uint8
+	mode       uint8
+	multicart  bool
+}
+
+func newMBC1(rom, ram []uint8, multicart bool) *mbc1 {
+	return &mbc1{
+		rom:       rom,
+		ram:       ram,
+		bank1:     1,
+		multicart: multicart,
+	}
+}
+
+// Returns whether the provided ROM image belongs to an MBC1M multicart. These
+// are 1 MiB images holding four 256 KiB games, each one with its own header.
+func isMBC1M(rom []uint8) bool {
+	const gameSize = 0x40000
+
+	if len(rom) != 4*gameSize {
+		return false
+	}
+
+	return bytes.Equal(rom[logoStart:logoEnd], rom[gameSize+logoStart:gameSize+logoEnd])
+}
+
+// Returns the number of bits bank1 contributes to the ROM bank number.
+func (c *mbc1) bank1Bits() uint {
+	if c.multicart {
+		return 4
+	}
+
+	return 5
+}
+
+// Returns the ROM bank mapped into 0x0000-0x3FFF. It is always bank 0, unless
+// the controller is in mode 1, where bank2 selects bank 0x00, 0x20, 0x40 or
+// 0x60 on large cartridges.
+func (c *mbc1) romBank0() int {
+	if c.mode == 0 {
+		return 0
+	}
+
+	return int(c.bank2) << c.bank1Bits()
+}
+
+// Returns the ROM bank mapped into 0x4000-0x7FFF.
+func (c *mbc1) romBankN() int {
+	mask := uint8(1)<<c.bank1Bits() - 1
+	return int(c.bank2)<<c.bank1Bits() | int(c.bank1&mask)
+}
+
+// Returns the RAM bank mapped into 0xA000-0xBFFF, which bank2 only selects
+// when the controller is in mode 1.
+func (c *mbc1) ramBank() int {
+	if c.mode == 0 {
+		return 0
+	}
+
+	return int(c.bank2)
+}
+
+// Load returns the contents of the cartridge at the provided address.
+func (c *mbc1) Load(addr uint16) uint8 {
+	switch {
+	case addr <= romBank0End:
+		return romByte(c.rom, c.romBank0(), addr)
+	case addr <= romBankNEnd:
+		return romByte(c.rom, c.romBankN(), addr-romBankN)
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if b := ramByte(c.ram, c.ramBank(), addr-externalRAM); c.ramEnabled && b != nil {
+			return *b
+		}
+	}
+
+	return 0xFF
+}
+
+// Store writes to the registers of the controller when addr falls in the ROM,
+// and saves the provided value into the external RAM otherwise.
+//
+// 0x0000-0x1FFF enables the external RAM when the lower nibble is 0xA.
+// 0x2000-0x3FFF sets bank1, the lower 5 bits of the ROM bank number. Writing
+// 0 selects bank 1 instead, which makes banks 0x20, 0x40 and 0x60 unreachable.
+// 0x4000-0x5FFF sets bank2, the upper 2 bits of the ROM or the RAM bank number.
+// 0x6000-0x7FFF selects the banking mode.
+func (c *mbc1) Store(addr uint16, b uint8) bool {
+	switch {
+	case addr <= 0x1FFF:
+		c.ramEnabled = b&0x0F == 0x0A
+	case addr <= 0x3FFF:
+		c.bank1 = b & 0x1F
+		if c.bank1 == 0 {
+			c.bank1 = 1
+		}
+	case addr <= 0x5FFF:
+		c.bank2 = b & 0x03
+	case addr <= 0x7FFF:
+		c.mode = b & 0x01
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if p := ramByte(c.ram, c.ramBank(), addr-externalRAM); c.ramEnabled && p != nil {
+			return storeRAM(p, b)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,131 @@
+package cartridge
+
+import (
+	"fmt"
+	"testing"
+)
+
+type write struct {
+	address uint16
+	b       uint8
+}
+
+func TestMBC1ROMBanking(t *testing.T) {
+	var testCases = []struct {
+		size   int
+		writes []write
+		bank0  uint8
+		bankN  uint8
+	}{
+		{0x80000, nil, 0x00, 0x01},
+		{0x80000, []write{{0x2000, 0x00}}, 0x00, 0x01},
+		{0x80000, []write{{0x2000, 0x05}}, 0x00, 0x05},
+		{0x80000, []write{{0x2000, 0xFF}}, 0x00, 0x1F},
+		{0x40000, []write{{0x2000, 0x1F}}, 0x00, 0x0F},
+		{0x200000, []write{{0x4000, 0x01}}, 0x00, 0x21},
+		{0x200000, []write{{0x4000, 0x02}, {0x2000, 0x00}}, 0x00, 0x41},
+		{0x200000, []write{{0x4000, 0x03}, {0x2000, 0x02}}, 0x00, 0x62},
+		{0x200000, []write{{0x4000, 0x03}, {0x6000, 0x01}}, 0x60, 0x61},
+		{0x200000, []write{{0x4000, 0x01}, {0x6000, 0x01}, {0x6000, 0x00}}, 0x00, 0x21},
+		{0x80000, []write{{0x4000, 0x01}, {0x6000, 0x01}, {0x2000, 0x03}}, 0x00, 0x03},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("case=%d size=0x%X", i, tc.size), func(t *testing.T) {
+			cart, err := New(NewROM(0x01, tc.size, 0x00))
+			if err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+
+			for _, w := range tc.writes {
+				cart.Store(w.address, w.b)
+			}
+
+			if bank0 := cart.Load(0x3FFF); bank0 != tc.bank0 {
+				t.Errorf("0x0000-0x3FFF: got bank 0x%02X, expected 0x%02X", bank0, tc.bank0)
+			}
+
+			if bankN := cart.Load(0x4000); bankN != tc.bankN {
+				t.Errorf("0x4000-0x7FFF: got bank 0x%02X, expected 0x%02X", bankN, tc.bankN)
+			}
+		})
+	}
+}
+
+func TestMBC1RAM(t *testing.T) {
+	cart, err := New(NewROM(0x03, 0x80000, 0x03))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	cart.Store(0xA000, 0x12)
+	if b := cart.Load(0xA000); b != 0xFF {
+		t.Errorf("disabled: got 0x%02X, expected 0xFF", b)
+	}
+
+	cart.Store(0x0000, 0x0A)
+	cart.Store(0xA000, 0x12)
+	if b := cart.Load(0xA000); b != 0x12 {
+		t.Errorf("enabled: got 0x%02X, expected 0x12", b)
+	}
+
+	// bank2 only selects the RAM bank in mode 1.
+	cart.Store(0x4000, 0x02)
+	if b := cart.Load(0xA000); b != 0x12 {
+		t.Errorf("mode 0: got 0x%02X, expected 0x12", b)
+	}
+
+	cart.Store(0x6000, 0x01)
+	if b := cart.Load(0xA000); b != 0x00 {
+		t.Errorf("mode 1: got 0x%02X, expected 0x00", b)
+	}
+
+	cart.Store(0xA000, 0x34)
+	cart.Store(0x4000, 0x00)
+	if b := cart.Load(0xA000); b != 0x12 {
+		t.Errorf("bank 0: got 0x%02X, expected 0x12", b)
+	}
+
+	cart.Store(0x0000, 0x00)
+	if b := cart.Load(0xA000); b != 0xFF {
+		t.Errorf("disabled: got 0x%02X, expected 0xFF", b)
+	}
+}
+
+func TestMBC1M(t *testing.T) {
+	rom := NewROM(0x01, 0x100000, 0x00)
+	copy(rom[0x40000+logoStart:], rom[logoStart:logoEnd])
+	FixChecksums(rom)
+
+	cart, err := New(rom)
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	var testCases = []struct {
+		writes []write
+		bank0  uint8
+		bankN  uint8
+	}{
+		{[]write{{0x2000, 0x1F}}, 0x00, 0x0F},
+		{[]write{{0x4000, 0x01}}, 0x00, 0x1F},
+		{[]write{{0x6000, 0x01}}, 0x10, 0x1F},
+		{[]write{{0x4000, 0x03}, {0x2000, 0x02}}, 0x30, 0x32},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("case=%d", i), func(t *testing.T) {
+			for _, w := range tc.writes {
+				cart.Store(w.address, w.b)
+			}
+
+			if bank0 := cart.Load(0x0200); bank0 != tc.bank0 {
+				t.Errorf("0x0000-0x3FFF: got bank 0x%02X, expected 0x%02X", bank0, tc.bank0)
+			}
+
+			if bankN := cart.Load(0x4000); bankN != tc.bankN {
+				t.Errorf("0x4000-0x7FFF: got bank 0x%02X, expected 0x%02X", bankN, tc.bankN)
+			}
+		})
+	}
+}
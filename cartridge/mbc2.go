@@ -0,0 +1,66 @@
+package cartridge
+
+// Size of the RAM built into the MBC2, in 4-bit cells.
+const mbc2RAMSize = 512
+
+// mbc2 is the MBC2 memory bank controller. It supports up to 256 KiB of ROM,
+// and has 512 cells of 4-bit RAM built in, mirrored across 0xA000-0xBFFF.
+type mbc2 struct {
+	rom []uint8
+	ram []uint8
+
+	ramEnabled bool
+	romBank    uint8
+}
+
+func newMBC2(rom, ram []uint8) *mbc2 {
+	return &mbc2{
+		rom:     rom,
+		ram:     ram,
+		romBank: 1,
+	}
+}
+
+// Load returns the contents of the cartridge at the provided address. The
+// upper 4 bits of the built-in RAM cells are not connected and read as 1.
+func (c *mbc2) Load(addr uint16) uint8 {
+	switch {
+	case addr <= romBank0End:
+		return romByte(c.rom, 0, addr)
+	case addr <= romBankNEnd:
+		return romByte(c.rom, int(c.romBank), addr-romBankN)
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if c.ramEnabled {
+			return 0xF0 | c.ram[(addr-externalRAM)%mbc2RAMSize]
+		}
+	}
+
+	return 0xFF
+}
+
+// Store writes to the registers of the controller when addr falls in
+// 0x0000-0x3FFF, and saves the lower 4 bits of the provided value into the
+// built-in RAM when addr falls in 0xA000-0xBFFF.
+//
+// Bit 8 of the address selects the register being written to. When reset,
+// the RAM is enabled if the lower nibble is 0xA. When set, the lower 4 bits
+// select the ROM bank, with 0 selecting bank 1 instead.
+func (c *mbc2) Store(addr uint16, b uint8) bool {
+	switch {
+	case addr <= 0x3FFF:
+		if addr&0x0100 == 0 {
+			c.ramEnabled = b&0x0F == 0x0A
+			return false
+		}
+		c.romBank = b & 0x0F
+		if c.romBank == 0 {
+			c.romBank = 1
+		}
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if c.ramEnabled {
+			return storeRAM(&c.ram[(addr-externalRAM)%mbc2RAMSize], b&0x0F)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package cartridge
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMBC2(t *testing.T) {
+	cart, err := New(NewROM(0x06, 0x40000, 0x00))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	var testCases = []struct {
+		writes []write
+		bankN  uint8
+	}{
+		{nil, 0x01},
+		{[]write{{0x2100, 0x00}}, 0x01},
+		{[]write{{0x2100, 0x0F}}, 0x0F},
+		{[]write{{0x3F00, 0x15}}, 0x05},
+		// Writes with bit 8 of the address reset do not touch the ROM bank.
+		{[]write{{0x2000, 0x03}}, 0x05},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("case=%d", i), func(t *testing.T) {
+			for _, w := range tc.writes {
+				cart.Store(w.address, w.b)
+			}
+
+			if bankN := cart.Load(0x4000); bankN != tc.bankN {
+				t.Errorf("got bank 0x%02X, expected 0x%02X", bankN, tc.bankN)
+			}
+		})
+	}
+
+	cart.Store(0xA000, 0x05)
+	if b := cart.Load(0xA000); b != 0xFF {
+		t.Errorf("disabled: got 0x%02X, expected 0xFF", b)
+	}
+
+	// Only the lower nibble is stored, and 512 cells are mirrored across the
+	// whole region.
+	cart.Store(0x0000, 0x0A)
+	cart.Store(0xA000, 0xA5)
+	if b := cart.Load(0xA200); b != 0xF5 {
+		t.Errorf("got 0x%02X, expected 0xF5", b)
+	}
+}
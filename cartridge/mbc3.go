@@ -0,0 +1,109 @@
+package cartridge
+
+// Largest ROM supported by the MBC3. Larger images use the MBC30, which has
+// an 8-bit ROM bank register.
+const mbc3MaxROMSize = 0x200000
+
+// mbc3 is the MBC3 memory bank controller. It supports up to 2 MiB of ROM and
+// 32 KiB of external RAM, and some carry a real-time clock whose registers are
+// mapped into 0xA000-0xBFFF in place of a RAM bank.
+type mbc3 struct {
+	rom []uint8
+	ram []uint8
+	rtc *rtc
+
+	ramEnabled bool
+	romBank    uint8
+	romMask    uint8
+
+	// ramBank selects a RAM bank when in 0x00-0x07, and a real-time clock
+	// register when in 0x08-0x0C.
+	ramBank uint8
+
+	// latchPrimed is true when the last write to 0x6000-0x7FFF was 0x00. The
+	// clock is latched when it is followed by a write of 0x01.
+	latchPrimed bool
+}
+
+func newMBC3(rom, ram []uint8, rtc *rtc) *mbc3 {
+	romMask := uint8(0x7F)
+	if len(rom) > mbc3MaxROMSize {
+		romMask = 0xFF
+	}
+
+	return &mbc3{
+		rom:     rom,
+		ram:     ram,
+		rtc:     rtc,
+		romBank: 1,
+		romMask: romMask,
+	}
+}
+
+// Returns whether the currently selected bank is a real-time clock register.
+func (c *mbc3) rtcSelected() bool {
+	return c.rtc != nil && c.ramBank >= rtcSeconds && c.ramBank <= rtcDaysHigh
+}
+
+// Load returns the contents of the cartridge at the provided address.
+func (c *mbc3) Load(addr uint16) uint8 {
+	switch {
+	case addr <= romBank0End:
+		return romByte(c.rom, 0, addr)
+	case addr <= romBankNEnd:
+		return romByte(c.rom, int(c.romBank), addr-romBankN)
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if !c.ramEnabled {
+			break
+		}
+		if c.rtcSelected() {
+			return c.rtc.load(c.ramBank)
+		}
+		if b := ramByte(c.ram, int(c.ramBank), addr-externalRAM); c.ramBank < rtcSeconds && b != nil {
+			return *b
+		}
+	}
+
+	return 0xFF
+}
+
+// Store writes to the registers of the controller when addr falls in the ROM,
+// and saves the provided value into the external RAM or the selected
+// real-time clock register otherwise.
+//
+// 0x0000-0x1FFF enables the external RAM and clock when the lower nibble is
+// 0xA.
+// 0x2000-0x3FFF selects the ROM bank. Writing 0 selects bank 1 instead.
+// 0x4000-0x5FFF selects the RAM bank or the real-time clock register.
+// 0x6000-0x7FFF latches the clock when 0x00 and 0x01 are written in sequence.
+func (c *mbc3) Store(addr uint16, b uint8) bool {
+	switch {
+	case addr <= 0x1FFF:
+		c.ramEnabled = b&0x0F == 0x0A
+	case addr <= 0x3FFF:
+		c.romBank = b & c.romMask
+		if c.romBank == 0 {
+			c.romBank = 1
+		}
+	case addr <= 0x5FFF:
+		c.ramBank = b
+	case addr <= 0x7FFF:
+		if c.latchPrimed && b == 0x01 && c.rtc != nil {
+			c.rtc.latch()
+		}
+		c.latchPrimed = b == 0x00
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if !c.ramEnabled {
+			return false
+		}
+		if c.rtcSelected() {
+			c.rtc.store(c.ramBank, b)
+			return true
+		}
+		if p := ramByte(c.ram, int(c.ramBank), addr-externalRAM); c.ramBank < rtcSeconds && p != nil {
+			return storeRAM(p, b)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,180 @@
+package cartridge
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newMBC3Cartridge(t *testing.T, clock Clock) *Cartridge {
+	cart, err := New(NewROM(0x10, 0x200000, 0x03), WithClock(clock))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	cart.Store(0x0000, 0x0A)
+
+	return cart
+}
+
+// Latches the clock of the provided cartridge and returns the latched
+// registers, from seconds to the upper day counter.
+func latchRTC(cart *Cartridge) (regs [5]uint8) {
+	cart.Store(0x6000, 0x00)
+	cart.Store(0x6000, 0x01)
+
+	for i := range regs {
+		cart.Store(0x4000, rtcSeconds+uint8(i))
+		regs[i] = cart.Load(0xA000)
+	}
+
+	return regs
+}
+
+func TestMBC3ROMBanking(t *testing.T) {
+	cart := newMBC3Cartridge(t, &fakeClock{})
+
+	var testCases = []struct {
+		b     uint8
+		bankN uint8
+	}{
+		{0x00, 0x01},
+		{0x01, 0x01},
+		{0x20, 0x20},
+		{0x7F, 0x7F},
+		{0xFF, 0x7F},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("b=0x%02X", tc.b), func(t *testing.T) {
+			cart.Store(0x2000, tc.b)
+
+			if bank0 := cart.Load(0x3FFF); bank0 != 0x00 {
+				t.Errorf("0x0000-0x3FFF: got bank 0x%02X, expected 0x00", bank0)
+			}
+
+			if bankN := cart.Load(0x4000); bankN != tc.bankN {
+				t.Errorf("0x4000-0x7FFF: got bank 0x%02X, expected 0x%02X", bankN, tc.bankN)
+			}
+		})
+	}
+}
+
+func TestMBC3RAMBanking(t *testing.T) {
+	cart := newMBC3Cartridge(t, &fakeClock{})
+
+	for bank := uint8(0); bank < 4; bank++ {
+		cart.Store(0x4000, bank)
+		cart.Store(0xA123, 0x10+bank)
+	}
+
+	for bank := uint8(0); bank < 4; bank++ {
+		cart.Store(0x4000, bank)
+		if b := cart.Load(0xA123); b != 0x10+bank {
+			t.Errorf("bank %d: got 0x%02X, expected 0x%02X", bank, b, 0x10+bank)
+		}
+	}
+}
+
+func TestMBC3RTC(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000000, 0)}
+	cart := newMBC3Cartridge(t, clock)
+
+	var testCases = []struct {
+		advance time.Duration
+		regs    [5]uint8
+	}{
+		{0, [5]uint8{0, 0, 0, 0, 0}},
+		{500 * time.Millisecond, [5]uint8{0, 0, 0, 0, 0}},
+		{500 * time.Millisecond, [5]uint8{1, 0, 0, 0, 0}},
+		{59 * time.Second, [5]uint8{0, 1, 0, 0, 0}},
+		{23*time.Hour + 59*time.Minute, [5]uint8{0, 0, 0, 1, 0}},
+		{255 * 24 * time.Hour, [5]uint8{0, 0, 0, 0, rtcDayHigh}},
+		{256*24*time.Hour + time.Hour + 2*time.Minute + 3*time.Second, [5]uint8{3, 2, 1, 0, rtcCarry}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("case=%d", i), func(t *testing.T) {
+			clock.advance(tc.advance)
+
+			if regs := latchRTC(cart); regs != tc.regs {
+				t.Errorf("got %v, expected %v", regs, tc.regs)
+			}
+		})
+	}
+}
+
+func TestMBC3RTCLatch(t *testing.T) {
+	clock := &fakeClock{}
+	cart := newMBC3Cartridge(t, clock)
+
+	latchRTC(cart)
+	clock.advance(5 * time.Second)
+
+	cart.Store(0x4000, rtcSeconds)
+	if b := cart.Load(0xA000); b != 0 {
+		t.Errorf("before latching: got %d, expected 0", b)
+	}
+
+	// Writing 0x01 without a preceding 0x00 does not latch the clock.
+	cart.Store(0x6000, 0x01)
+	if b := cart.Load(0xA000); b != 0 {
+		t.Errorf("after unprimed latch: got %d, expected 0", b)
+	}
+
+	cart.Store(0x6000, 0x00)
+	cart.Store(0x6000, 0x01)
+	if b := cart.Load(0xA000); b != 5 {
+		t.Errorf("after latching: got %d, expected 5", b)
+	}
+}
+
+func TestMBC3RTCHalt(t *testing.T) {
+	clock := &fakeClock{}
+	cart := newMBC3Cartridge(t, clock)
+
+	cart.Store(0x4000, rtcDaysHigh)
+	cart.Store(0xA000, rtcHalt)
+	clock.advance(time.Hour)
+
+	if regs := latchRTC(cart); regs != [5]uint8{0, 0, 0, 0, rtcHalt} {
+		t.Errorf("halted: got %v, expected %v", regs, [5]uint8{0, 0, 0, 0, rtcHalt})
+	}
+
+	cart.Store(0x4000, rtcDaysHigh)
+	cart.Store(0xA000, 0x00)
+	clock.advance(time.Minute)
+
+	if regs := latchRTC(cart); regs != [5]uint8{0, 1, 0, 0, 0} {
+		t.Errorf("resumed: got %v, expected %v", regs, [5]uint8{0, 1, 0, 0, 0})
+	}
+}
+
+func TestMBC3RTCOutOfRange(t *testing.T) {
+	clock := &fakeClock{}
+	cart := newMBC3Cartridge(t, clock)
+
+	// Counters holding values the clock could not have counted up to wrap
+	// around at their bit width without carrying over.
+	cart.Store(0x4000, rtcSeconds)
+	cart.Store(0xA000, 63)
+	cart.Store(0x4000, rtcMinutes)
+	cart.Store(0xA000, 59)
+	clock.advance(2 * time.Second)
+
+	if regs := latchRTC(cart); regs != [5]uint8{1, 59, 0, 0, 0} {
+		t.Errorf("got %v, expected %v", regs, [5]uint8{1, 59, 0, 0, 0})
+	}
+}
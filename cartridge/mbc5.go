@@ -0,0 +1,90 @@
+package cartridge
+
+// Bit of the RAM bank register that drives the motor of rumble cartridges.
+const mbc5Rumble uint8 = 1 << 3
+
+// mbc5 is the MBC5 memory bank controller. It supports up to 8 MiB of ROM,
+// through a 9-bit ROM bank number, and 128 KiB of external RAM. Rumble
+// cartridges wire bit 3 of the RAM bank register to a motor instead.
+type mbc5 struct {
+	rom []uint8
+	ram []uint8
+
+	ramEnabled bool
+	romBank    uint16
+	ramBank    uint8
+
+	rumble   bool
+	motor    bool
+	onRumble func(on bool)
+}
+
+func newMBC5(rom, ram []uint8, rumble bool, onRumble func(on bool)) *mbc5 {
+	return &mbc5{
+		rom:      rom,
+		ram:      ram,
+		romBank:  1,
+		rumble:   rumble,
+		onRumble: onRumble,
+	}
+}
+
+// Load returns the contents of the cartridge at the provided address.
+func (c *mbc5) Load(addr uint16) uint8 {
+	switch {
+	case addr <= romBank0End:
+		return romByte(c.rom, 0, addr)
+	case addr <= romBankNEnd:
+		return romByte(c.rom, int(c.romBank), addr-romBankN)
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if b := ramByte(c.ram, int(c.ramBank), addr-externalRAM); c.ramEnabled && b != nil {
+			return *b
+		}
+	}
+
+	return 0xFF
+}
+
+// Store writes to the registers of the controller when addr falls in the ROM,
+// and saves the provided value into the external RAM otherwise.
+//
+// 0x0000-0x1FFF enables the external RAM when 0x0A is written.
+// 0x2000-0x2FFF sets the lower 8 bits of the ROM bank number. Unlike earlier
+// controllers, bank 0 can be mapped into 0x4000-0x7FFF.
+// 0x3000-0x3FFF sets bit 8 of the ROM bank number.
+// 0x4000-0x5FFF selects the RAM bank, and drives the motor of rumble
+// cartridges through bit 3.
+func (c *mbc5) Store(addr uint16, b uint8) bool {
+	switch {
+	case addr <= 0x1FFF:
+		c.ramEnabled = b == 0x0A
+	case addr <= 0x2FFF:
+		c.romBank = c.romBank&0x100 | uint16(b)
+	case addr <= 0x3FFF:
+		c.romBank = uint16(b&0x01)<<8 | c.romBank&0xFF
+	case addr <= 0x5FFF:
+		if c.rumble {
+			c.setMotor(b&mbc5Rumble != 0)
+			b &^= mbc5Rumble
+		}
+		c.ramBank = b & 0x0F
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if p := ramByte(c.ram, int(c.ramBank), addr-externalRAM); c.ramEnabled && p != nil {
+			return storeRAM(p, b)
+		}
+	}
+
+	return false
+}
+
+// Turns the motor on or off, notifying the rumble handler of any change.
+func (c *mbc5) setMotor(on bool) {
+	if on == c.motor {
+		return
+	}
+
+	c.motor = on
+	if c.onRumble != nil {
+		c.onRumble(on)
+	}
+}
@@ -0,0 +1,94 @@
+package cartridge
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMBC5ROMBanking(t *testing.T) {
+	cart, err := New(NewROM(0x19, 0x800000, 0x00))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	var testCases = []struct {
+		writes []write
+		offset int
+	}{
+		{nil, 0x01},
+		{[]write{{0x2000, 0x00}}, 0x00},
+		{[]write{{0x2000, 0xFF}}, 0xFF},
+		{[]write{{0x3000, 0x01}}, 0x1FF},
+		{[]write{{0x2000, 0x02}}, 0x102},
+		{[]write{{0x3000, 0xFE}}, 0x02},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("case=%d", i), func(t *testing.T) {
+			for _, w := range tc.writes {
+				cart.Store(w.address, w.b)
+			}
+
+			// Every byte of a bank holds the lower 8 bits of the bank number.
+			if bankN := cart.Load(0x4000); bankN != uint8(tc.offset) {
+				t.Errorf("got bank 0x%02X, expected 0x%02X", bankN, uint8(tc.offset))
+			}
+		})
+	}
+}
+
+func TestMBC5RAMBanking(t *testing.T) {
+	cart, err := New(NewROM(0x1B, 0x8000, 0x04))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	// Only 0x0A enables the external RAM.
+	cart.Store(0x0000, 0x1A)
+	cart.Store(0xA000, 0x12)
+	if b := cart.Load(0xA000); b != 0xFF {
+		t.Errorf("disabled: got 0x%02X, expected 0xFF", b)
+	}
+
+	cart.Store(0x0000, 0x0A)
+	for bank := uint8(0); bank < 16; bank++ {
+		cart.Store(0x4000, bank)
+		cart.Store(0xBFFF, 0x20+bank)
+	}
+
+	for bank := uint8(0); bank < 16; bank++ {
+		cart.Store(0x4000, bank)
+		if b := cart.Load(0xBFFF); b != 0x20+bank {
+			t.Errorf("bank %d: got 0x%02X, expected 0x%02X", bank, b, 0x20+bank)
+		}
+	}
+}
+
+func TestMBC5Rumble(t *testing.T) {
+	var events []bool
+	cart, err := New(NewROM(0x1E, 0x8000, 0x03), WithRumbleHandler(func(on bool) {
+		events = append(events, on)
+	}))
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	cart.Store(0x0000, 0x0A)
+	cart.Store(0x4000, 0x01)
+	cart.Store(0xA000, 0x34)
+
+	for _, b := range []uint8{0x09, 0x09, 0x01, 0x08} {
+		cart.Store(0x4000, b)
+	}
+
+	expected := []bool{true, false, true}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Errorf("got %v, expected %v", events, expected)
+	}
+
+	// The motor bit does not select a RAM bank.
+	cart.Store(0x4000, 0x09)
+	if b := cart.Load(0xA000); b != 0x34 {
+		t.Errorf("got 0x%02X, expected 0x34", b)
+	}
+}
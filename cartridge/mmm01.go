@@ -0,0 +1,139 @@
+package cartridge
+
+// Size of the part of an MMM01 image mapped into 0x0000-0x7FFF before the
+// menu locks the mapping.
+const mmm01MenuSize = 0x8000
+
+// mmm01 is the MMM01 memory bank controller, found in multicarts. On power
+// up it maps the menu stored in the last 32 KiB of the image. The menu then
+// selects a game by setting the upper bits of the ROM and RAM bank numbers
+// and locks the mapping, after which the controller behaves like an MBC1
+// confined to the selected game.
+type mmm01 struct {
+	rom []uint8
+	ram []uint8
+
+	locked     bool
+	ramEnabled bool
+
+	// romLow holds bits 0-4 of the ROM bank number, romMid bits 5-6 and
+	// romHigh bits 7-8.
+	romLow  uint8
+	romMid  uint8
+	romHigh uint8
+	// romMask holds the bits of romLow that are frozen when the mapping is
+	// locked, shrinking the game down to a fraction of 512 KiB.
+	romMask uint8
+
+	// ramLow holds bits 0-1 of the RAM bank number and ramHigh bits 2-3.
+	ramLow  uint8
+	ramHigh uint8
+
+	mode       uint8
+	modeLocked bool
+	frozenLow  uint8
+}
+
+func newMMM01(rom, ram []uint8) *mmm01 {
+	return &mmm01{rom: rom, ram: ram}
+}
+
+// Returns the ROM bank mapped into 0x0000-0x3FFF, or into 0x4000-0x7FFF when
+// high is true.
+func (c *mmm01) romBank(high bool) int {
+	if !c.locked {
+		// The menu lives in the last two banks of the image.
+		banks := len(c.rom) / romBankSize
+		if high {
+			return banks - 1
+		}
+		return banks - 2
+	}
+
+	low := c.frozenLow & c.romMask
+	if high {
+		bank := c.romLow &^ c.romMask
+		if bank == 0 {
+			bank = 1
+		}
+		low |= bank
+	}
+
+	return int(c.romHigh)<<7 | int(c.romMid)<<5 | int(low)
+}
+
+// Returns the RAM bank mapped into 0xA000-0xBFFF. Like on the MBC1, the
+// lower bits only select the RAM bank in mode 1.
+func (c *mmm01) ramBank() int {
+	if c.mode == 0 {
+		return int(c.ramHigh) << 2
+	}
+
+	return int(c.ramHigh)<<2 | int(c.ramLow)
+}
+
+// Load returns the contents of the cartridge at the provided address.
+func (c *mmm01) Load(addr uint16) uint8 {
+	switch {
+	case addr <= romBank0End:
+		return romByte(c.rom, c.romBank(false), addr)
+	case addr <= romBankNEnd:
+		return romByte(c.rom, c.romBank(true), addr-romBankN)
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if b := ramByte(c.ram, c.ramBank(), addr-externalRAM); c.ramEnabled && b != nil {
+			return *b
+		}
+	}
+
+	return 0xFF
+}
+
+// Store writes to the registers of the controller when addr falls in the ROM,
+// and saves the provided value into the external RAM otherwise. The bits that
+// select the game can only be written to before the mapping is locked.
+//
+// 0x0000-0x1FFF enables the external RAM when the lower nibble is 0xA. Bit 6
+// locks the mapping.
+// 0x2000-0x3FFF sets bits 0-4 of the ROM bank number, and bits 5-6 through
+// bits 5-6 of the value.
+// 0x4000-0x5FFF sets bits 0-1 of the RAM bank number, bits 2-3 of the RAM
+// bank number through bits 2-3 of the value, and bits 7-8 of the ROM bank
+// number through bits 4-5 of the value. Bit 6 prevents further changes to the
+// banking mode.
+// 0x6000-0x7FFF selects the banking mode through bit 0, and sets the ROM bank
+// mask through bits 2-5.
+func (c *mmm01) Store(addr uint16, b uint8) bool {
+	switch {
+	case addr <= 0x1FFF:
+		c.ramEnabled = b&0x0F == 0x0A
+		if !c.locked && b&0x40 != 0 {
+			c.locked = true
+			c.frozenLow = c.romLow
+		}
+	case addr <= 0x3FFF:
+		c.romLow = b & 0x1F
+		if !c.locked {
+			c.romMid = b >> 5 & 0x03
+		}
+	case addr <= 0x5FFF:
+		c.ramLow = b & 0x03
+		if !c.locked {
+			c.ramHigh = b >> 2 & 0x03
+			c.romHigh = b >> 4 & 0x03
+			c.modeLocked = b&0x40 != 0
+		}
+	case addr <= 0x7FFF:
+		if !c.modeLocked {
+			c.mode = b & 0x01
+		}
+		if !c.locked {
+			c.romMask = (b >> 2 & 0x0F) << 1
+		}
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if p := ramByte(c.ram, c.ramBank(), addr-externalRAM); c.ramEnabled && p != nil {
+			return storeRAM(p, b)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package cartridge
+
+import "testing"
+
+// Returns a 128 KiB MMM01 multicart whose menu header is stored in the last
+// 32 KiB. The header at the start of the image describes the first game, and
+// its global checksum does not match the whole image, as in real dumps.
+func newMMM01ROM() []uint8 {
+	rom := NewROM(0x01, 0x20000, 0x00)
+	menu := rom[len(rom)-mmm01MenuSize:]
+	copy(menu[addrTitle:headerEnd+1], rom[addrTitle:headerEnd+1])
+	menu[addrType] = 0x0D
+	menu[addrRAMSize] = 0x03
+	menu[addrHeaderChecksum] = HeaderChecksum(menu)
+	rom[addrGlobalChecksum] ^= 0xFF
+
+	return rom
+}
+
+func TestMMM01Header(t *testing.T) {
+	rom := newMMM01ROM()
+	cart, err := New(rom)
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+	if cart.Header.Type != 0x0D {
+		t.Errorf("type: got 0x%02X, expected 0x0D", uint8(cart.Header.Type))
+	}
+	if cart.Header.RAMSize != 0x8000 {
+		t.Errorf("RAM size: got %d, expected %d", cart.Header.RAMSize, 0x8000)
+	}
+
+	// The header checksum of the menu is still verified.
+	rom[len(rom)-mmm01MenuSize+addrTitle]++
+	if _, err := New(rom); err == nil {
+		t.Error("got nil, expected checksum error")
+	}
+}
+
+func TestMMM01(t *testing.T) {
+	cart, err := New(newMMM01ROM())
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	if cart.Header.Type != 0x0D {
+		t.Errorf("got type 0x%02X, expected 0x0D", uint8(cart.Header.Type))
+	}
+
+	// The menu is mapped on power up.
+	if bank0, bankN := cart.Load(0x2000), cart.Load(0x4000); bank0 != 6 || bankN != 7 {
+		t.Errorf("menu: got banks %d and %d, expected 6 and 7", bank0, bankN)
+	}
+
+	// Select the 64 KiB game starting from bank 4 and lock the mapping.
+	cart.Store(0x2000, 0x04)
+	cart.Store(0x6000, 0x0E<<2)
+	cart.Store(0x0000, 0x40)
+	if bank0, bankN := cart.Load(0x2000), cart.Load(0x4000); bank0 != 4 || bankN != 5 {
+		t.Errorf("locked: got banks %d and %d, expected 4 and 5", bank0, bankN)
+	}
+
+	// Once locked, the game can only switch between its own banks.
+	cart.Store(0x2000, 0x03)
+	if bank0, bankN := cart.Load(0x2000), cart.Load(0x4000); bank0 != 4 || bankN != 7 {
+		t.Errorf("got banks %d and %d, expected 4 and 7", bank0, bankN)
+	}
+	cart.Store(0x2000, 0x00)
+	if bankN := cart.Load(0x4000); bankN != 5 {
+		t.Errorf("got bank %d, expected 5", bankN)
+	}
+
+	cart.Store(0x0000, 0x0A)
+	cart.Store(0xA000, 0x12)
+	if b := cart.Load(0xA000); b != 0x12 {
+		t.Errorf("got 0x%02X, expected 0x12", b)
+	}
+}
@@ -0,0 +1,41 @@
+package cartridge
+
+// romOnly is a cartridge without a memory bank controller. Its ROM is mapped
+// directly into 0x0000-0x7FFF, and some carry up to 8 KiB of external RAM.
+type romOnly struct {
+	rom []uint8
+	ram []uint8
+}
+
+func newROMOnly(rom, ram []uint8) *romOnly {
+	return &romOnly{rom: rom, ram: ram}
+}
+
+// Load returns the contents of the cartridge at the provided address.
+func (c *romOnly) Load(addr uint16) uint8 {
+	switch {
+	case addr <= romBankNEnd:
+		if int(addr) >= len(c.rom) {
+			return 0xFF
+		}
+		return c.rom[addr]
+	case addr >= externalRAM && addr <= externalRAMEnd:
+		if b := ramByte(c.ram, 0, addr-externalRAM); b != nil {
+			return *b
+		}
+	}
+
+	return 0xFF
+}
+
+// Store saves the provided value into the external RAM of the cartridge.
+// Writes to the ROM are ignored.
+func (c *romOnly) Store(addr uint16, b uint8) bool {
+	if addr >= externalRAM && addr <= externalRAMEnd {
+		if p := ramByte(c.ram, 0, addr-externalRAM); p != nil {
+			return storeRAM(p, b)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,238 @@
+package cartridge
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// Clock is the interface that wraps the Now method, which cartridges with a
+// real-time clock use to tell how much time has passed.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Bits of the upper day counter register of a real-time clock.
+const (
+	rtcDayHigh uint8 = 1 << 0
+	rtcHalt    uint8 = 1 << 6
+	rtcCarry   uint8 = 1 << 7
+)
+
+// Real-time clock register selectors, written to 0x4000-0x5FFF.
+const (
+	rtcSeconds uint8 = iota + 0x08
+	rtcMinutes
+	rtcHours
+	rtcDaysLow
+	rtcDaysHigh
+)
+
+// rtcRegisters holds the counters of a real-time clock. days is 9 bits wide;
+// its 9th bit is exposed through bit 0 of the upper day counter register.
+type rtcRegisters struct {
+	seconds uint8
+	minutes uint8
+	hours   uint8
+	days    uint16
+	halt    bool
+	carry   bool
+}
+
+// Returns whether the counters hold values the clock itself could have
+// counted up to.
+func (r *rtcRegisters) valid() bool {
+	return r.seconds < 60 && r.minutes < 60 && r.hours < 24
+}
+
+// Advances the counters by one second. Counters holding out of range values
+// written by the game wrap around at their bit width without carrying over.
+func (r *rtcRegisters) tick() {
+	if r.seconds = (r.seconds + 1) & 0x3F; r.seconds != 60 {
+		return
+	}
+	r.seconds = 0
+
+	if r.minutes = (r.minutes + 1) & 0x3F; r.minutes != 60 {
+		return
+	}
+	r.minutes = 0
+
+	if r.hours = (r.hours + 1) & 0x1F; r.hours != 24 {
+		return
+	}
+	r.hours = 0
+
+	r.addDays(1)
+}
+
+// Adds the provided number of days to the day counter, setting the carry bit
+// if the 9-bit counter overflows.
+func (r *rtcRegisters) addDays(days int64) {
+	total := int64(r.days) + days
+	if total > 0x1FF {
+		r.carry = true
+	}
+	r.days = uint16(total % 0x200)
+}
+
+// Advances the counters by the provided number of seconds.
+func (r *rtcRegisters) advance(secs int64) {
+	for ; secs > 0 && !r.valid(); secs-- {
+		r.tick()
+	}
+
+	if secs <= 0 {
+		return
+	}
+
+	total := int64(r.hours)*3600 + int64(r.minutes)*60 + int64(r.seconds) + secs
+	r.seconds = uint8(total % 60)
+	r.minutes = uint8(total / 60 % 60)
+	r.hours = uint8(total / 3600 % 24)
+	r.addDays(total / 86400)
+}
+
+// Returns the value of the provided register.
+func (r *rtcRegisters) load(reg uint8) uint8 {
+	switch reg {
+	case rtcSeconds:
+		return r.seconds
+	case rtcMinutes:
+		return r.minutes
+	case rtcHours:
+		return r.hours
+	case rtcDaysLow:
+		return uint8(r.days)
+	case rtcDaysHigh:
+		b := uint8(r.days>>8) & rtcDayHigh
+		if r.halt {
+			b |= rtcHalt
+		}
+		if r.carry {
+			b |= rtcCarry
+		}
+		return b
+	default:
+		return 0xFF
+	}
+}
+
+// Sets the value of the provided register.
+func (r *rtcRegisters) store(reg uint8, b uint8) {
+	switch reg {
+	case rtcSeconds:
+		r.seconds = b & 0x3F
+	case rtcMinutes:
+		r.minutes = b & 0x3F
+	case rtcHours:
+		r.hours = b & 0x1F
+	case rtcDaysLow:
+		r.days = r.days&0x100 | uint16(b)
+	case rtcDaysHigh:
+		r.days = uint16(b&rtcDayHigh)<<8 | r.days&0xFF
+		r.halt = b&rtcHalt != 0
+		r.carry = b&rtcCarry != 0
+	}
+}
+
+// rtc is the real-time clock found in MBC3 cartridges. Reads see the latched
+// copy of the counters, which is only refreshed when the game latches the
+// clock, while writes go to the counters themselves.
+type rtc struct {
+	clock   Clock
+	counter rtcRegisters
+	latched rtcRegisters
+
+	// last is the time the counters were last brought up to date. The time
+	// passed since then that does not add up to a whole second is carried
+	// over to the next update.
+	last time.Time
+}
+
+func newRTC(clock Clock) *rtc {
+	return &rtc{clock: clock, last: clock.Now()}
+}
+
+// Brings the counters up to date with the time source.
+func (r *rtc) update() {
+	now := r.clock.Now()
+	if r.counter.halt || now.Before(r.last) {
+		r.last = now
+		return
+	}
+
+	secs := now.Sub(r.last) / time.Second
+	r.counter.advance(int64(secs))
+	r.last = r.last.Add(secs * time.Second)
+}
+
+// Copies the counters into the latched registers.
+func (r *rtc) latch() {
+	r.update()
+	r.latched = r.counter
+}
+
+// Returns the value of the provided latched register.
+func (r *rtc) load(reg uint8) uint8 {
+	return r.latched.load(reg)
+}
+
+// Sets the value of the provided register. Writing to the seconds register
+// also resets the sub-second counter.
+func (r *rtc) store(reg uint8, b uint8) {
+	r.update()
+	r.counter.store(reg, b)
+	r.latched.store(reg, b)
+
+	if reg == rtcSeconds {
+		r.last = r.clock.Now()
+	}
+}
+
+// Sizes of the real-time clock footer appended to save files in the format
+// used by BGB and VBA-M. The footer holds the counters and the latched
+// registers as 32-bit values, followed by the time it was saved as a 64-bit
+// Unix timestamp. Older versions used a 32-bit timestamp instead.
+const (
+	rtcFooterSize       = 48
+	rtcFooterSizeLegacy = 44
+)
+
+// Order of the registers in the real-time clock footer.
+var rtcFooterRegisters = [...]uint8{rtcSeconds, rtcMinutes, rtcHours, rtcDaysLow, rtcDaysHigh}
+
+// Returns the state of the clock encoded as a save file footer.
+func (r *rtc) marshal() []uint8 {
+	r.update()
+
+	b := make([]uint8, rtcFooterSize)
+	for i, reg := range rtcFooterRegisters {
+		binary.LittleEndian.PutUint32(b[4*i:], uint32(r.counter.load(reg)))
+		binary.LittleEndian.PutUint32(b[20+4*i:], uint32(r.latched.load(reg)))
+	}
+	binary.LittleEndian.PutUint64(b[40:], uint64(r.last.Unix()))
+
+	return b
+}
+
+// Restores the state of the clock from the provided save file footer, then
+// brings it up to date with the time passed since it was saved.
+func (r *rtc) unmarshal(b []uint8) {
+	for i, reg := range rtcFooterRegisters {
+		r.counter.store(reg, uint8(binary.LittleEndian.Uint32(b[4*i:])))
+		r.latched.store(reg, uint8(binary.LittleEndian.Uint32(b[20+4*i:])))
+	}
+
+	if len(b) >= rtcFooterSize {
+		r.last = time.Unix(int64(binary.LittleEndian.Uint64(b[40:])), 0)
+	} else {
+		r.last = time.Unix(int64(binary.LittleEndian.Uint32(b[40:])), 0)
+	}
+	r.update()
+}
@@ -0,0 +1,89 @@
+package cartridge
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultFlushInterval is how often a Saver writes modified save data to disk
+// by default.
+const DefaultFlushInterval = 5 * time.Second
+
+// SavePath returns the path of the save file kept next to the provided ROM
+// file, sharing its name with a .sav extension.
+func SavePath(romPath string) string {
+	return strings.TrimSuffix(romPath, filepath.Ext(romPath)) + ".sav"
+}
+
+// Saver keeps the save data of a battery-backed cartridge in a file.
+type Saver struct {
+	cart     *Cartridge
+	path     string
+	interval time.Duration
+	last     time.Time
+}
+
+// NewSaver returns a Saver that keeps the save data of the provided cartridge
+// in the file at path, writing it at most once every interval.
+func NewSaver(cart *Cartridge, path string, interval time.Duration) *Saver {
+	return &Saver{cart: cart, path: path, interval: interval, last: time.Now()}
+}
+
+// Load restores the save data of the cartridge from the file. A missing file
+// is not an error, and leaves the cartridge as it is.
+func (s *Saver) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.cart.LoadSaveData(data)
+}
+
+// Flush writes the save data of the cartridge to the file. The data is first
+// written to a temporary file which then replaces the save file, so that a
+// crash while writing never leaves a truncated save behind.
+func (s *Saver) Flush() error {
+	s.last = time.Now()
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(s.cart.SaveData()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// MaybeFlush writes the save data of the cartridge to the file if it has been
+// modified and the flush interval has passed since it was last written.
+func (s *Saver) MaybeFlush() error {
+	if !s.cart.Dirty() || time.Since(s.last) < s.interval {
+		return nil
+	}
+
+	return s.Flush()
+}
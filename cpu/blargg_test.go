@@ -0,0 +1,131 @@
+package cpu
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/loizoskounios/game-boy-emulator/mmu"
+)
+
+// Blargg's test ROMs are not distributed along with the emulator. They are
+// looked up in blarggDir, laid out as in the gb-test-roms repository, and the
+// ones missing are skipped.
+const blarggDir = "testdata/blargg"
+
+var blarggROMs = []string{
+	"cpu_instrs/individual/01-special.gb",
+	"cpu_instrs/individual/02-interrupts.gb",
+	"cpu_instrs/individual/03-op sp,hl.gb",
+	"cpu_instrs/individual/04-op r,imm.gb",
+	"cpu_instrs/individual/05-op rp.gb",
+	"cpu_instrs/individual/06-ld r,r.gb",
+	"cpu_instrs/individual/07-jr,jp,call,ret,rst.gb",
+	"cpu_instrs/individual/08-misc instrs.gb",
+	"cpu_instrs/individual/09-op r,r.gb",
+	"cpu_instrs/individual/10-bit ops.gb",
+	"cpu_instrs/individual/11-op a,(hl).gb",
+	"instr_timing/instr_timing.gb",
+}
+
+// blarggTimeout is the number of frames a test ROM is given to report its
+// result: a minute of emulated time.
+const blarggTimeout = 60 * 60
+
+// Addresses of the result a test ROM reports in cartridge RAM, next to the
+// one it prints over the serial port.
+const (
+	blarggStatus    uint16 = 0xA000
+	blarggSignature uint16 = 0xA001
+	blarggText      uint16 = 0xA004
+
+	// blarggRunning is the status reported while the test is running.
+	blarggRunning uint8 = 0x80
+)
+
+// Returns whether the memory at blarggSignature holds the signature that
+// marks the memory-based result as valid.
+func blarggSigned(m *mmu.MemoryManagementUnit) bool {
+	for i, b := range []uint8{0xDE, 0xB0, 0x61} {
+		if m.Load(blarggSignature+uint16(i)) != b {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the null-terminated text reported in cartridge RAM.
+func blarggMemoryText(m *mmu.MemoryManagementUnit) string {
+	var text []uint8
+	for addr := blarggText; addr < 0xC000; addr++ {
+		b := m.Load(addr)
+		if b == 0 {
+			break
+		}
+		text = append(text, b)
+	}
+
+	return string(text)
+}
+
+// Runs the provided test ROM until it reports its result, either over the
+// serial port or in cartridge RAM. Returns the text it reported and whether
+// it passed.
+func runBlargg(t *testing.T, rom []uint8) (string, bool) {
+	m := mmu.New()
+	if err := m.LoadROM(rom); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	var out bytes.Buffer
+	m.Serial().Connect(&out)
+
+	cpu := New(WithMMU(m), SkipBoot(mmu.ModelDMG))
+	for frame := 0; frame < blarggTimeout; frame++ {
+		if err := cpu.RunFrame(); err != nil {
+			t.Fatalf("%v, output so far:\n%s", err, out.String())
+		}
+
+		switch text := out.String(); {
+		case strings.Contains(text, "Passed"):
+			return text, true
+		case strings.Contains(text, "Failed"):
+			return text, false
+		}
+
+		if blarggSigned(m) {
+			if status := m.Load(blarggStatus); status != blarggRunning {
+				return blarggMemoryText(m), status == 0
+			}
+		}
+	}
+
+	t.Fatalf("timed out after %d frames, output so far:\n%s", blarggTimeout, out.String())
+
+	return "", false
+}
+
+func TestBlargg(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test ROMs in short mode")
+	}
+
+	for _, name := range blarggROMs {
+		t.Run(name, func(t *testing.T) {
+			rom, err := os.ReadFile(filepath.Join(blarggDir, name))
+			if os.IsNotExist(err) {
+				t.Skipf("%s not found in %s", name, blarggDir)
+			}
+			if err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+
+			if text, passed := runBlargg(t, rom); !passed {
+				t.Errorf("failed:\n%s", text)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package cpu
+
+import "github.com/loizoskounios/game-boy-emulator/mmu"
+
+// Address of the cartridge header checksum, which determines the state of the
+// H and C flags after the DMG and MGB boot ROMs.
+const headerChecksumAddress = 0x014D
+
+// postBootRegisters holds, for each model, the values of paired registers AF,
+// BC, DE and HL after the boot ROM hands control over to the cartridge.
+var postBootRegisters = map[mmu.Model][4]uint16{
+	mmu.ModelDMG: {0x01B0, 0x0013, 0x00D8, 0x014D},
+	mmu.ModelMGB: {0xFFB0, 0x0013, 0x00D8, 0x014D},
+	mmu.ModelSGB: {0x0100, 0x0014, 0x0000, 0xC060},
+	mmu.ModelCGB: {0x1180, 0x0000, 0xFF56, 0x000D},
+}
+
+// SkipBoot makes the CPU skip the boot ROM of the provided model. Execution
+// starts at 0x0100, with the registers and the I/O registers holding the
+// values the boot ROM would have left them in.
+func SkipBoot(model mmu.Model) Option {
+	return func(cpu *CPU) {
+		cpu.skipBoot = true
+		cpu.model = model
+	}
+}
+
+// Puts the CPU and the MMU in the state the boot ROM leaves them in.
+func (cpu *CPU) skipBootROM() {
+	regs := postBootRegisters[cpu.model]
+
+	cpu.r.SetAF(regs[0])
+	cpu.r.SetPaired(RegisterBC, regs[1])
+	cpu.r.SetPaired(RegisterDE, regs[2])
+	cpu.r.SetPaired(RegisterHL, regs[3])
+	*cpu.r.StackPointer() = 0xFFFE
+	*cpu.r.ProgramCounter() = 0x0100
+
+	// The DMG and MGB boot ROMs leave the H and C flags set unless the header
+	// checksum happens to be zero.
+	if cpu.model == mmu.ModelDMG || cpu.model == mmu.ModelMGB {
+		checksum := cpu.mmu.Load(headerChecksumAddress) != 0
+		cpu.r.PutFlag(FlagH, checksum)
+		cpu.r.PutFlag(FlagC, checksum)
+	}
+
+	cpu.mmu.SkipBoot(cpu.model)
+}
@@ -1,10 +1,6 @@
 package cpu
 
-import (
-	"fmt"
-
-	"github.com/loizoskounios/game-boy-emulator/mmu"
-)
+import "github.com/loizoskounios/game-boy-emulator/mmu"
 
 // CPU is the CPU.
 type CPU struct {
@@ -12,58 +8,169 @@ type CPU struct {
 	i   *instruction
 	r   *Registers
 	mmu *mmu.MemoryManagementUnit
+
+	// bus is what memory is accessed through, which is the MMU unless a test
+	// replaces it.
+	bus bus
+
+	model    mmu.Model
+	skipBoot bool
+
+	// ime is the interrupt master enable flag. imeDelay counts down the
+	// instructions left until a pending EI sets it.
+	ime      bool
+	imeDelay int
+
+	halted  bool
+	haltBug bool
+	stopped bool
+
+	// opcodeAddr is the address the opcode of the instruction being executed
+	// was fetched from.
+	opcodeAddr uint16
+
+	// overrun is the number of machine cycles the last frame ran past its
+	// end.
+	overrun int
+
+	// err is set when the CPU locks up, after which it stops executing
+	// instructions.
+	err error
+
+	tracer Tracer
 }
 
-// New returns a new CPU struct.
-func New() *CPU {
-	c := NewClock(0)
-	i := &instruction{}
-	r := NewRegisters()
-	mmu := mmu.New()
+// bus is the memory bus of the CPU. Each access takes a machine cycle, during
+// which the rest of the system is ticked before memory is accessed.
+type bus interface {
+	Load(addr uint16) uint8
+	Store(addr uint16, b uint8)
+	Tick()
+}
+
+// Option configures a CPU created by New.
+type Option func(*CPU)
 
-	return &CPU{
-		c:   c,
-		i:   i,
-		r:   r,
-		mmu: mmu,
+// WithMMU makes the CPU use the provided memory management unit instead of a
+// new, empty one. This is how a CPU is attached to a loaded cartridge.
+func WithMMU(mmu *mmu.MemoryManagementUnit) Option {
+	return func(cpu *CPU) {
+		cpu.mmu = mmu
 	}
 }
 
-// Rst resets the CPU to the start of execution.
-func (cpu *CPU) Rst() {
-	cpu = New()
+// New returns a new CPU struct configured by the provided options.
+func New(opts ...Option) *CPU {
+	cpu := &CPU{mmu: mmu.New()}
+	for _, opt := range opts {
+		opt(cpu)
+	}
+	cpu.bus = cpu.mmu
+
+	cpu.powerUp()
+
+	return cpu
 }
 
-// Dispatch loop.
-func (cpu *CPU) Dispatch() {
-	opcode := uint8(0)
-	currInstructionSet := &instructions
-	pc := cpu.r.ProgramCounter()
+// Rst resets the CPU and every component attached to it to their power-up
+// state, as if the console was switched off and on again with the same
+// cartridge inserted. The battery-backed RAM of the cartridge is kept if
+// keepBatteryRAM is true.
+func (cpu *CPU) Rst(keepBatteryRAM bool) {
+	cpu.mmu.Reset(keepBatteryRAM)
 
-	for i := 0; i < 256; i++ {
-		if opcode == 0xCB {
-			currInstructionSet = &instructionsCB
-		} else {
-			currInstructionSet = &instructions
-		}
+	*cpu = CPU{
+		mmu:      cpu.mmu,
+		bus:      cpu.bus,
+		model:    cpu.model,
+		skipBoot: cpu.skipBoot,
+		tracer:   cpu.tracer,
+	}
+	cpu.powerUp()
+}
+
+// Puts the registers and the clock in their power-up state, then skips the
+// boot ROM if asked to.
+func (cpu *CPU) powerUp() {
+	cpu.c = NewClock(0)
+	cpu.i = &instruction{}
+	cpu.r = NewRegisters()
 
-		opcode = cpu.memByte(*pc)
-		cpu.i = currInstructionSet[opcode]
-		fmt.Println(cpu.i)
-		cpu.i.execute(cpu)
+	if cpu.skipBoot {
+		cpu.skipBootROM()
 	}
 }
 
-// Nop does nothing.
-func (cpu *CPU) Nop() {
-	cpu.r.IncrementProgramCounter(1)
-	cpu.c.AddM(1)
+// Step executes a single instruction, CB-prefixed ones included, services an
+// interrupt, or idles for a machine cycle while halted or stopped. The rest of
+// the system is advanced along with every machine cycle, so that each memory
+// access sees it as it is at that point of the instruction. Returns the
+// machine cycles taken, along with the error that locked up the CPU, if any.
+func (cpu *CPU) Step() (int, error) {
+	start := cpu.c.M()
+	cpu.step()
+
+	return int(cpu.c.M() - start), cpu.err
+}
+
+func (cpu *CPU) step() {
+	if cpu.stopped {
+		cpu.idleStopped()
+		return
+	}
+
+	// A locked up CPU no longer fetches instructions nor services interrupts,
+	// but the rest of the system keeps running.
+	if cpu.err != nil {
+		cpu.tick()
+		return
+	}
+
+	if cpu.halted && !cpu.wake() {
+		cpu.tick()
+		return
+	}
+
+	if cpu.serviceInterrupt() {
+		return
+	}
+
+	if cpu.tracer != nil {
+		cpu.tracer.Trace(cpu.State())
+	}
+
+	cpu.execute()
 }
 
-// CB switches to the CB instruction set.
+// Fetches the opcode at the program counter and executes the instruction it
+// maps to.
+func (cpu *CPU) execute() {
+	pc := cpu.r.ProgramCounter()
+	cpu.opcodeAddr = *pc
+	opcode := cpu.memByte(*pc)
+
+	// The HALT bug makes the CPU read the opcode following HALT without
+	// incrementing the program counter, so the byte is read again.
+	if cpu.haltBug {
+		cpu.haltBug = false
+	} else {
+		*pc++
+	}
+
+	cpu.i = instructions[opcode]
+	cpu.i.execute(cpu)
+
+	cpu.updateIME()
+}
+
+// Nop does nothing.
+func (cpu *CPU) Nop() {}
+
+// CB fetches the opcode following the prefix of a CB-prefixed instruction and
+// executes it, as part of the same instruction.
 func (cpu *CPU) CB() {
-	cpu.r.IncrementProgramCounter(1)
-	cpu.c.AddM(1)
+	cpu.i = instructionsCB[cpu.memImmediateByte()]
+	cpu.i.execute(cpu)
 }
 
 /**
@@ -72,22 +179,16 @@ func (cpu *CPU) CB() {
 
 func (cpu *CPU) load8(val uint8, dst *uint8) {
 	*dst = val
-
-	cpu.c.AddM(1)
 }
 
 // LoadAIntoA loads the contents of the accumulator into the accumulator.
 func (cpu *CPU) LoadAIntoA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.load8(*acc, acc)
 }
 
 // LoadRIntoA loads the contents of the provided register into the accumulator.
 func (cpu *CPU) LoadRIntoA(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	acc := cpu.r.Accumulator()
 	cpu.load8(*a, acc)
@@ -95,8 +196,6 @@ func (cpu *CPU) LoadRIntoA(r Register) {
 
 // LoadAIntoR loads the contents of the accumulator into the provided register.
 func (cpu *CPU) LoadAIntoR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.load8(*acc, a)
@@ -104,8 +203,6 @@ func (cpu *CPU) LoadAIntoR(r Register) {
 
 // LoadRIntoR loads the contents of register from into register to.
 func (cpu *CPU) LoadRIntoR(from, to Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	f, _ := cpu.r.Auxiliary(from)
 	t, _ := cpu.r.Auxiliary(to)
 	cpu.load8(*f, t)
@@ -114,8 +211,6 @@ func (cpu *CPU) LoadRIntoR(from, to Register) {
 // LoadNIntoA loads the contents of the memory address specified by the 8-bit
 // immediate operand into the accumulator.
 func (cpu *CPU) LoadNIntoA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	n := cpu.memImmediateByte()
 	acc := cpu.r.Accumulator()
 	cpu.load8(n, acc)
@@ -124,8 +219,6 @@ func (cpu *CPU) LoadNIntoA() {
 // LoadNIntoR loads the contents of the memory address specified by the 8-bit
 // immediate operand into the provided register.
 func (cpu *CPU) LoadNIntoR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	n := cpu.memImmediateByte()
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.load8(n, a)
@@ -134,8 +227,6 @@ func (cpu *CPU) LoadNIntoR(r Register) {
 // LoadRRIntoA loads the contents of the memory address specified by the
 // provided paired register into the accumulator.
 func (cpu *CPU) LoadRRIntoA(rr Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	pr, _ := cpu.r.Paired(rr)
 	val := cpu.memByte(pr)
 
@@ -146,8 +237,6 @@ func (cpu *CPU) LoadRRIntoA(rr Register) {
 // LoadHLIntoR loads the contents of the memory address specified by register
 // HL into the provided register.
 func (cpu *CPU) LoadHLIntoR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 
@@ -158,40 +247,28 @@ func (cpu *CPU) LoadHLIntoR(r Register) {
 // LoadAIntoHL loads the contents of the accumulator into the memory address
 // specified by register HL.
 func (cpu *CPU) LoadAIntoHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	hl, _ := cpu.r.Paired(RegisterHL)
 
 	cpu.memStoreByte(hl, *acc)
-
-	cpu.c.AddM(1)
 }
 
 // LoadRIntoHL loads the contents of the provided register into the memory
 // address specified by register HL.
 func (cpu *CPU) LoadRIntoHL(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	hl, _ := cpu.r.Paired(RegisterHL)
 
 	cpu.memStoreByte(hl, *a)
-
-	cpu.c.AddM(1)
 }
 
 // LoadNIntoHL loads the contents of the memory specified by the 8-bit immediate
 // operand into the memory address specified by register HL.
 func (cpu *CPU) LoadNIntoHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	n := cpu.memImmediateByte()
 
 	hl, _ := cpu.r.Paired(RegisterHL)
 	cpu.memStoreByte(hl, n)
-
-	cpu.c.AddM(1)
 }
 
 func (cpu *CPU) offsetC() uint16 {
@@ -202,8 +279,6 @@ func (cpu *CPU) offsetC() uint16 {
 // LoadOffsetCIntoA loads the contents of the memory address specified by the
 // addition of register C and constant offset 0xFF00 into register A.
 func (cpu *CPU) LoadOffsetCIntoA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	address := cpu.offsetC()
 	val := cpu.memByte(address)
 
@@ -214,13 +289,9 @@ func (cpu *CPU) LoadOffsetCIntoA() {
 // LoadAIntoOffsetC loads the contents of register A into the memory address
 // specified by the addition of register C and constant offset 0xFF00.
 func (cpu *CPU) LoadAIntoOffsetC() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	address := cpu.offsetC()
 	cpu.memStoreByte(address, *acc)
-
-	cpu.c.AddM(1)
 }
 
 func (cpu *CPU) offsetImmediate() uint16 {
@@ -231,8 +302,6 @@ func (cpu *CPU) offsetImmediate() uint16 {
 // by the addition of the 8-bit immediate operand and constant offset 0xFF00
 // into register A.
 func (cpu *CPU) LoadOffsetImmediateIntoA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	address := cpu.offsetImmediate()
 	val := cpu.memByte(address)
 
@@ -244,37 +313,27 @@ func (cpu *CPU) LoadOffsetImmediateIntoA() {
 // address specified by the addition of the 8-bit immediate operand and constant
 // offset 0xFF00.
 func (cpu *CPU) LoadAIntoOffsetImmediate() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	address := cpu.offsetImmediate()
 	cpu.memStoreByte(address, *acc)
-
-	cpu.c.AddM(1)
 }
 
 // LoadNNIntoA loads the contents of the memory address specified by the 16-bit
 // immediate operand into register A.
 func (cpu *CPU) LoadNNIntoA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	address := cpu.memImmediateWord()
-	acc := cpu.r.Accumulator()
-	cpu.memStoreByte(address, *acc)
+	val := cpu.memByte(address)
 
-	cpu.c.AddM(1)
+	acc := cpu.r.Accumulator()
+	cpu.load8(val, acc)
 }
 
 // LoadAIntoNN loads the contents of register A into the memory address
 // specified by the 16-bit immediate operand.
 func (cpu *CPU) LoadAIntoNN() {
-	cpu.r.IncrementProgramCounter(1)
-
 	address := cpu.memImmediateWord()
-	val := cpu.memByte(address)
-
 	acc := cpu.r.Accumulator()
-	cpu.load8(val, acc)
+	cpu.memStoreByte(address, *acc)
 }
 
 // LoadHLIntoAIncrementHL loads the contents of the memory address specified by
@@ -294,27 +353,19 @@ func (cpu *CPU) LoadHLIntoADecrementHL() {
 // LoadAIntoBC loads the contents of the accumulator into the memory address
 // specified by register BC.
 func (cpu *CPU) LoadAIntoBC() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	bc, _ := cpu.r.Paired(RegisterBC)
 
 	cpu.memStoreByte(bc, *acc)
-
-	cpu.c.AddM(1)
 }
 
 // LoadAIntoDE loads the contents of the accumulator into the memory address
 // specified by register DE.
 func (cpu *CPU) LoadAIntoDE() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	de, _ := cpu.r.Paired(RegisterDE)
 
 	cpu.memStoreByte(de, *acc)
-
-	cpu.c.AddM(1)
 }
 
 // LoadAIntoHLIncrementHL loads the contents of the accumulator into the memory
@@ -337,15 +388,11 @@ func (cpu *CPU) LoadAIntoHLDecrementHL() {
 
 func (cpu *CPU) load16(val uint16, rr Register) {
 	cpu.r.SetPaired(rr, val)
-
-	cpu.c.AddM(1)
 }
 
 // LoadNNIntoRR loads the 16-bit immediate operand into the provided paired
 // register.
 func (cpu *CPU) LoadNNIntoRR(rr Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	val := cpu.memImmediateWord()
 	cpu.load16(val, rr)
 }
@@ -353,42 +400,38 @@ func (cpu *CPU) LoadNNIntoRR(rr Register) {
 // LoadHLIntoSP loads the contents of register HL into the stack pointer
 // register.
 func (cpu *CPU) LoadHLIntoSP() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	sp := cpu.r.StackPointer()
 	*sp = hl
 
-	cpu.c.AddM(2)
+	cpu.tick()
 }
 
 // Pushes the provided word onto the stack, then decrements the stack pointer
 // by 2.
 // The 8 most significant bits of the word are stored in Memory[SP-1].
 // The 8 least significant bits of the word are stored in Memory[SP-2].
+// The stack pointer is decremented during a machine cycle of its own, before
+// the most significant bits are stored.
 func (cpu *CPU) pushWordOntoStack(word uint16) {
+	cpu.tick()
+
 	sp := cpu.r.StackPointer()
-	*sp -= 2
-	cpu.memStoreWord(*sp, word)
+	*sp--
+	cpu.memStoreByte(*sp, uint8(word>>8))
+	*sp--
+	cpu.memStoreByte(*sp, uint8(word))
 }
 
 // PushAFOntoStack pushes the paired register AF onto the stack.
 func (cpu *CPU) PushAFOntoStack() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.pushWordOntoStack(cpu.r.AF())
-
-	cpu.c.AddM(2)
 }
 
 // PushRROntoStack pushes the contents of the provided paired register onto the stack.
 func (cpu *CPU) PushRROntoStack(rr Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	word, _ := cpu.r.Paired(rr)
 	cpu.pushWordOntoStack(word)
-
-	cpu.c.AddM(2)
 }
 
 // Pops a word from the stack, then increments the stack pointer by 2.
@@ -405,45 +448,34 @@ func (cpu *CPU) popStack() uint16 {
 // PopStackIntoAF pops a word from the stack and loads it into paired
 // register AF.
 func (cpu *CPU) PopStackIntoAF() {
-	cpu.r.IncrementProgramCounter(1)
-
-	cpu.r.SetAF(cpu.popStack())
-
-	cpu.c.AddM(1)
+	// The lower nibble of the flags register always reads as 0.
+	cpu.r.SetAF(cpu.popStack() &^ 0x000F)
 }
 
 // PopStackIntoRR pops a word from the stack and loads it into the provided
 // paired register.
 func (cpu *CPU) PopStackIntoRR(to Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.r.SetPaired(to, cpu.popStack())
-
-	cpu.c.AddM(1)
 }
 
 // LoadOffsetSPIntoHL loads the result of the addition of the stack pointer and
 // the 8-bit operand, with the operand being treated as a signed integer in
 // the range [-128, 127], into register HL. Flags are updated accordingly.
 func (cpu *CPU) LoadOffsetSPIntoHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	sp := cpu.r.StackPointer()
 	b := cpu.memImmediateByte()
 	offsetSP := cpu.add16S8(*sp, b)
 	cpu.r.SetPaired(RegisterHL, offsetSP)
+
+	cpu.tick()
 }
 
 // LoadSPIntoNN loads the stack pointer register into the memory address
 // specified by the immediate 16-bit operand.
 func (cpu *CPU) LoadSPIntoNN() {
-	cpu.r.IncrementProgramCounter(1)
-
 	address := cpu.memImmediateWord()
 	sp := cpu.r.StackPointer()
 	cpu.memStoreWord(address, *sp)
-
-	cpu.c.AddM(1)
 }
 
 /**
@@ -472,8 +504,6 @@ func (cpu *CPU) add8(x, y uint8, useCarry bool) (result uint8) {
 	cpu.r.PutFlag(FlagH, halfCarryOut)
 	cpu.r.PutFlag(FlagZ, result == 0)
 
-	cpu.c.AddM(1)
-
 	return result
 }
 
@@ -486,16 +516,12 @@ func (cpu *CPU) add8Helper(y uint8, useCarry bool, f func(Flag) error) {
 // AddA adds the accumulator to itself, storing the result in the accumulator.
 // Flags are updated accordingly.
 func (cpu *CPU) AddA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.add8Helper(*cpu.r.Accumulator(), false, cpu.r.ResetFlag)
 }
 
 // AddR adds the provided register to the accumulator, storing the result in the
 // accumulator. Flags are updated accordingly.
 func (cpu *CPU) AddR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.add8Helper(*a, false, cpu.r.ResetFlag)
 }
@@ -503,8 +529,6 @@ func (cpu *CPU) AddR(r Register) {
 // AddN adds the immediate byte to the accumulator, storing the result in the
 // accumulator. Flags are updated accordingly.
 func (cpu *CPU) AddN() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.add8Helper(cpu.memImmediateByte(), false, cpu.r.ResetFlag)
 }
 
@@ -512,8 +536,6 @@ func (cpu *CPU) AddN() {
 // to the accumulator, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) AddHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	cpu.add8Helper(cpu.memByte(hl), false, cpu.r.ResetFlag)
 }
@@ -522,8 +544,6 @@ func (cpu *CPU) AddHL() {
 // accumulator itself, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) AdcA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.add8Helper(*cpu.r.Accumulator(), true, cpu.r.ResetFlag)
 }
 
@@ -531,8 +551,6 @@ func (cpu *CPU) AdcA() {
 // accumulator, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) AdcR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.add8Helper(*a, true, cpu.r.ResetFlag)
 }
@@ -541,8 +559,6 @@ func (cpu *CPU) AdcR(r Register) {
 // accumulator, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) AdcN() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.add8Helper(cpu.memImmediateByte(), true, cpu.r.ResetFlag)
 }
 
@@ -550,40 +566,42 @@ func (cpu *CPU) AdcN() {
 // and the contents of the carry flag to the accumulator, storing the result in
 // the accumulator. Flags are updated accordingly.
 func (cpu *CPU) AdcHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	cpu.add8Helper(cpu.memByte(hl), true, cpu.r.ResetFlag)
 }
 
-// Adapted from: https://stackoverflow.com/a/8037485/1283818
-func (cpu *CPU) sub8Helper(y uint8, useCarry bool, f func(Flag) error) {
-	// a - b - c = a + ^b + 1 - c = a + ^b + !c
-	// a - b = a + ^b + 1
-	y = ^y
-	if useCarry {
-		cpu.r.ToggleFlag(FlagC)
-		defer cpu.r.ToggleFlag(FlagC)
-	} else {
-		y++
+// Subtracts y, and the carry flag if useCarry is true, from x. The C and H
+// flags are set on a borrow from bit 8 and bit 4 respectively.
+func (cpu *CPU) sub8(x, y uint8, useCarry bool) (result uint8) {
+	var borrow uint8
+	if c, _ := cpu.r.IsFlagSet(FlagC); c && useCarry {
+		borrow = 1
 	}
 
-	cpu.add8Helper(y, useCarry, f)
+	result = x - y - borrow
+
+	cpu.r.PutFlag(FlagC, uint16(x) < uint16(y)+uint16(borrow))
+	cpu.r.PutFlag(FlagH, x&0x0F < y&0x0F+borrow)
+	cpu.r.PutFlag(FlagZ, result == 0)
+
+	return result
+}
+
+func (cpu *CPU) sub8Helper(y uint8, useCarry bool, f func(Flag) error) {
+	acc := cpu.r.Accumulator()
+	*acc = cpu.sub8(*acc, y, useCarry)
+	f(FlagN)
 }
 
 // SubA subtracts the accumulator from itself, storing the result in the
 // accumulator. Flags are updated accordingly.
 func (cpu *CPU) SubA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.sub8Helper(*cpu.r.Accumulator(), false, cpu.r.SetFlag)
 }
 
 // SubR subtracts the provided register from the accumulator, storing the result
 // in the accumulator. Flags are updated accordingly.
 func (cpu *CPU) SubR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.sub8Helper(*a, false, cpu.r.SetFlag)
 }
@@ -591,8 +609,6 @@ func (cpu *CPU) SubR(r Register) {
 // SubN subtracts the immediate byte from the accumulator, storing the result in
 // the accumulator. Flags are updated accordingly.
 func (cpu *CPU) SubN() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.sub8Helper(cpu.memImmediateByte(), false, cpu.r.SetFlag)
 }
 
@@ -600,8 +616,6 @@ func (cpu *CPU) SubN() {
 // register HL from the accumulator, storing the result in the accumulator.
 // Flags are updated accordingly.
 func (cpu *CPU) SubHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	cpu.sub8Helper(cpu.memByte(hl), false, cpu.r.SetFlag)
 }
@@ -609,8 +623,6 @@ func (cpu *CPU) SubHL() {
 // SbcA subtracts the accumulator and the contents of the carry flag from
 // itself, storing the result in the accumulator. Flags are updated accordingly.
 func (cpu *CPU) SbcA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.sub8Helper(*cpu.r.Accumulator(), true, cpu.r.SetFlag)
 }
 
@@ -618,8 +630,6 @@ func (cpu *CPU) SbcA() {
 // the accumulator, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) SbcR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.sub8Helper(*a, true, cpu.r.SetFlag)
 }
@@ -628,8 +638,6 @@ func (cpu *CPU) SbcR(r Register) {
 // accumulator, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) SbcN() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.sub8Helper(cpu.memImmediateByte(), true, cpu.r.SetFlag)
 }
 
@@ -637,8 +645,6 @@ func (cpu *CPU) SbcN() {
 // register HL and the contents of the carry flag from the accumulator,
 // storing the result in the accumulator. Flags are updated accordingly.
 func (cpu *CPU) SbcHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	cpu.sub8Helper(cpu.memByte(hl), true, cpu.r.SetFlag)
 }
@@ -656,16 +662,12 @@ func (cpu *CPU) and8(x, y uint8) (result uint8) {
 	cpu.r.ResetFlag(FlagN)
 	cpu.r.PutFlag(FlagZ, result == 0)
 
-	cpu.c.AddM(1)
-
 	return result
 }
 
 // AndA performs bitwise AND between the contents of the accumulator and itself,
 // storing the result in the accumulator. Flags are updated accordingly.
 func (cpu *CPU) AndA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.bitwise8Helper(*acc, cpu.and8)
 }
@@ -674,8 +676,6 @@ func (cpu *CPU) AndA() {
 // provided register, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) AndR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.bitwise8Helper(*a, cpu.and8)
 }
@@ -684,8 +684,6 @@ func (cpu *CPU) AndR(r Register) {
 // immediate byte, storing the result in the accumulator. Flags are updated
 // accordingly
 func (cpu *CPU) AndN() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.bitwise8Helper(cpu.memImmediateByte(), cpu.and8)
 }
 
@@ -693,8 +691,6 @@ func (cpu *CPU) AndN() {
 // value stored in the memory location referenced by register HL, storing the
 // result in the accumulator. Flags are updated accordingly.
 func (cpu *CPU) AndHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	cpu.bitwise8Helper(cpu.memByte(hl), cpu.and8)
 }
@@ -707,16 +703,12 @@ func (cpu *CPU) xor8(x, y uint8) (result uint8) {
 	cpu.r.ResetFlag(FlagN)
 	cpu.r.PutFlag(FlagZ, result == 0)
 
-	cpu.c.AddM(1)
-
 	return result
 }
 
 // XorA performs bitwise XOR between the contents of the accumulator and itself,
 // storing the result in the accumulator. Flags are updated accordingly.
 func (cpu *CPU) XorA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.bitwise8Helper(*acc, cpu.xor8)
 }
@@ -725,8 +717,6 @@ func (cpu *CPU) XorA() {
 // provided register, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) XorR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.bitwise8Helper(*a, cpu.xor8)
 }
@@ -735,8 +725,6 @@ func (cpu *CPU) XorR(r Register) {
 // immediate byte, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) XorN() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.bitwise8Helper(cpu.memImmediateByte(), cpu.xor8)
 }
 
@@ -744,8 +732,6 @@ func (cpu *CPU) XorN() {
 // value stored in the memory location referenced by register HL, storing the
 // result in the accumulator. Flags are updated accordingly.
 func (cpu *CPU) XorHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	cpu.bitwise8Helper(cpu.memByte(hl), cpu.xor8)
 }
@@ -758,16 +744,12 @@ func (cpu *CPU) or8(x, y uint8) (result uint8) {
 	cpu.r.ResetFlag(FlagN)
 	cpu.r.PutFlag(FlagZ, result == 0)
 
-	cpu.c.AddM(1)
-
 	return result
 }
 
 // OrA performs bitwise OR between the contents of the accumulator and itself,
 // storing the result in the accumulator. Flags are updated accordingly.
 func (cpu *CPU) OrA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.bitwise8Helper(*acc, cpu.or8)
 }
@@ -776,8 +758,6 @@ func (cpu *CPU) OrA() {
 // provided register, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) OrR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.bitwise8Helper(*a, cpu.or8)
 }
@@ -786,8 +766,6 @@ func (cpu *CPU) OrR(r Register) {
 // immediate byte, storing the result in the accumulator. Flags are updated
 // accordingly.
 func (cpu *CPU) OrN() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.bitwise8Helper(cpu.memImmediateByte(), cpu.or8)
 }
 
@@ -795,32 +773,25 @@ func (cpu *CPU) OrN() {
 // value stored in the memory location referenced by register HL, storing the
 // result in the accumulator. Flags are updated accordingly.
 func (cpu *CPU) OrHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	cpu.bitwise8Helper(cpu.memByte(hl), cpu.or8)
 }
 
-// See cpu.sub8Helper function.
 func (cpu *CPU) compare8Helper(y uint8) {
 	acc := cpu.r.Accumulator()
-	cpu.add8(*acc, ^y+1, false)
+	cpu.sub8(*acc, y, false)
 	cpu.r.SetFlag(FlagN)
 }
 
 // CompareA subtracts the accumulator from itself, discarding the result. Flags
 // are updated accordingly.
 func (cpu *CPU) CompareA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.compare8Helper(*cpu.r.Accumulator())
 }
 
 // CompareR subtracts the provided register from the accumulator, discarding the
 // result. Flags are updated accordingly.
 func (cpu *CPU) CompareR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.compare8Helper(*a)
 }
@@ -828,8 +799,6 @@ func (cpu *CPU) CompareR(r Register) {
 // CompareN subtracts the immediate byte from the accumulator, discarding the
 // result. Flags are updated accordingly.
 func (cpu *CPU) CompareN() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.compare8Helper(cpu.memImmediateByte())
 }
 
@@ -837,8 +806,6 @@ func (cpu *CPU) CompareN() {
 // register HL from the accumulator, discarding the result. Flags are updated
 // accordingly.
 func (cpu *CPU) CompareHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	cpu.compare8Helper(cpu.memByte(hl))
 }
@@ -857,15 +824,11 @@ func (cpu *CPU) increment8(x, by uint8) (result uint8) {
 func (cpu *CPU) increment8Helper(x *uint8) {
 	*x = cpu.increment8(*x, 1)
 	cpu.r.ResetFlag(FlagN)
-
-	cpu.c.AddM(1)
 }
 
 // IncrementA increments the accumulator register by 1. Flags are updated
 // accordingly.
 func (cpu *CPU) IncrementA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.increment8Helper(acc)
 }
@@ -873,8 +836,6 @@ func (cpu *CPU) IncrementA() {
 // IncrementR increments the provided register by 1. Flags are updated
 // accordingly.
 func (cpu *CPU) IncrementR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.increment8Helper(a)
 }
@@ -882,8 +843,6 @@ func (cpu *CPU) IncrementR(r Register) {
 // IncrementHL increments the memory contents referenced by register HL by 1.
 // Flags are updated accordingly.
 func (cpu *CPU) IncrementHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.increment8Helper(&val)
@@ -891,19 +850,16 @@ func (cpu *CPU) IncrementHL() {
 }
 
 func (cpu *CPU) decrement8Helper(x *uint8) {
-	by := uint8(1)
-	by = ^by + 1
-	*x = cpu.increment8(*x, by)
+	// The H flag is set on a borrow from bit 4.
+	cpu.r.PutFlag(FlagH, *x&0x0F == 0)
+	*x--
+	cpu.r.PutFlag(FlagZ, *x == 0)
 	cpu.r.SetFlag(FlagN)
-
-	cpu.c.AddM(1)
 }
 
 // DecrementA decrements the accumulator register by 1. Flags are updated
 // accordingly.
 func (cpu *CPU) DecrementA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.decrement8Helper(acc)
 }
@@ -911,8 +867,6 @@ func (cpu *CPU) DecrementA() {
 // DecrementR decrements the provided register by 1. Flags are updated
 // accordingly.
 func (cpu *CPU) DecrementR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.decrement8Helper(a)
 }
@@ -920,8 +874,6 @@ func (cpu *CPU) DecrementR(r Register) {
 // DecrementHL decrements the memory contents referenced by register HL by 1.
 // Flags are updated accordingly.
 func (cpu *CPU) DecrementHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.decrement8Helper(&val)
@@ -939,8 +891,6 @@ func (cpu *CPU) DecrementHL() {
 //
 // Adapted from: https://forums.nesdev.com/viewtopic.php?f=20&t=15944#p196282
 func (cpu *CPU) DecimalAdjustA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 
 	if n, _ := cpu.r.IsFlagSet(FlagN); !n {
@@ -964,46 +914,32 @@ func (cpu *CPU) DecimalAdjustA() {
 
 	cpu.r.ResetFlag(FlagH)
 	cpu.r.PutFlag(FlagZ, *acc == 0)
-
-	cpu.c.AddM(1)
 }
 
 // ComplementA sets the accumulator to the one's complement of itself. Flags are
 // updated accordingly.
 func (cpu *CPU) ComplementA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	*acc = ^*acc
 
 	cpu.r.SetFlag(FlagH)
 	cpu.r.SetFlag(FlagN)
-
-	cpu.c.AddM(1)
 }
 
 // ComplementCarryFlag toggles the carry flag. Flags are updated accordingly.
 func (cpu *CPU) ComplementCarryFlag() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.r.ToggleFlag(FlagC)
 
 	cpu.r.ResetFlag(FlagH)
 	cpu.r.ResetFlag(FlagN)
-
-	cpu.c.AddM(1)
 }
 
 // SetCarryFlag sets the carry flag. Flags are updated accordingly.
 func (cpu *CPU) SetCarryFlag() {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.r.SetFlag(FlagC)
 
 	cpu.r.ResetFlag(FlagH)
 	cpu.r.ResetFlag(FlagN)
-
-	cpu.c.AddM(1)
 }
 
 /**
@@ -1027,7 +963,7 @@ func (cpu *CPU) add16(x, y uint16) (result uint16) {
 	cpu.r.PutFlag(FlagH, halfCarryOut)
 	cpu.r.ResetFlag(FlagN)
 
-	cpu.c.AddM(2)
+	cpu.tick()
 
 	return result
 }
@@ -1040,8 +976,6 @@ func (cpu *CPU) add16Helper(y uint16) {
 // AddRR adds the provided register to register HL, storing the result in
 // register HL. Flags are updated accordingly.
 func (cpu *CPU) AddRR(rr Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	pr, _ := cpu.r.Paired(rr)
 	cpu.add16Helper(pr)
 }
@@ -1049,62 +983,50 @@ func (cpu *CPU) AddRR(rr Register) {
 // AddSP adds the stack pointer register to register HL, storing the result in
 // register HL. Flags are updated accordingly.
 func (cpu *CPU) AddSP() {
-	cpu.r.IncrementProgramCounter(1)
-
 	sp := cpu.r.StackPointer()
 	cpu.add16Helper(*sp)
 }
 
 // IncrementRR increments the provided register by 1.
 func (cpu *CPU) IncrementRR(rr Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.r.IncrementPaired(rr)
 
-	cpu.c.AddM(2)
+	cpu.tick()
 }
 
 // IncrementSP increments the stack pointer register by 1.
 func (cpu *CPU) IncrementSP() {
-	cpu.r.IncrementProgramCounter(1)
-
 	sp := cpu.r.StackPointer()
 	*sp++
 
-	cpu.c.AddM(2)
+	cpu.tick()
 }
 
 // DecrementRR decrements the provided register by 1.
 func (cpu *CPU) DecrementRR(rr Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	cpu.r.DecrementPaired(rr)
 
-	cpu.c.AddM(2)
+	cpu.tick()
 }
 
 // DecrementSP decrements the stack pointer register by 1.
 func (cpu *CPU) DecrementSP() {
-	cpu.r.IncrementProgramCounter(1)
-
 	sp := cpu.r.StackPointer()
 	*sp--
 
-	cpu.c.AddM(2)
+	cpu.tick()
 }
 
 // Adds uint8 to uint16 with uint8 being treated as a signed number in the range
-// [-128, 127].
+// [-128, 127]. The C and H flags are set on a carry out of bit 7 and bit 3,
+// as if y was added to the lower byte of x as an unsigned number. The Z and N
+// flags are reset.
 func (cpu *CPU) add16S8(x uint16, y uint8) (result uint16) {
-	var yy uint16
-	if y > 127 {
-		// Adding this to x is equal to subtracting ^y+1 from x.
-		yy = ^uint16(^y+1) + 1
-	} else {
-		yy = uint16(y)
-	}
+	result = x + uint16(int8(y))
 
-	result = cpu.add16(x, yy)
+	cpu.r.PutFlag(FlagC, x&0xFF+uint16(y) > 0xFF)
+	cpu.r.PutFlag(FlagH, x&0x0F+uint16(y&0x0F) > 0x0F)
+	cpu.r.ResetFlag(FlagN)
 	cpu.r.ResetFlag(FlagZ)
 
 	return result
@@ -1114,13 +1036,12 @@ func (cpu *CPU) add16S8(x uint16, y uint8) (result uint16) {
 // register, with the operand being treated as a signed integer in the range
 // [-128, 127]. Flags are updated accordingly.
 func (cpu *CPU) AddOffsetImmediateToSP() {
-	cpu.r.IncrementProgramCounter(1)
-
 	sp := cpu.r.StackPointer()
 	offsetSP := cpu.add16S8(*sp, cpu.memImmediateByte())
 	*sp = offsetSP
 
-	cpu.c.AddM(1)
+	cpu.tick()
+	cpu.tick()
 }
 
 /**
@@ -1137,8 +1058,6 @@ func (cpu *CPU) JumpHL() {
 	pc := cpu.r.ProgramCounter()
 	hl, _ := cpu.r.Paired(RegisterHL)
 	*pc = hl
-
-	cpu.c.AddM(1)
 }
 
 // JumpOffset loads the result of the addition of the program counter and the
@@ -1146,8 +1065,16 @@ func (cpu *CPU) JumpHL() {
 // in the range [-128, 127], into the program counter.
 func (cpu *CPU) JumpOffset() {
 	ib := cpu.memImmediateByte()
+	cpu.jumpOffset(ib)
+}
+
+// Adds the provided offset, treated as a signed integer, to the program
+// counter, which takes a machine cycle. Flags are left untouched.
+func (cpu *CPU) jumpOffset(e uint8) {
 	pc := cpu.r.ProgramCounter()
-	*pc = cpu.add16S8(*pc, ib)
+	*pc += uint16(int8(e))
+
+	cpu.tick()
 }
 
 // JumpOffsetConditionally loads the result of the addition of the program
@@ -1156,87 +1083,86 @@ func (cpu *CPU) JumpOffset() {
 // condition is that the status of the provided flag must match the provided
 // status.
 func (cpu *CPU) JumpOffsetConditionally(flag Flag, isSet bool) {
+	ib := cpu.memImmediateByte()
 	if cpu.shouldJump(flag, isSet) {
-		cpu.JumpOffset()
-	} else {
-		cpu.r.IncrementProgramCounter(1)
-		cpu.c.AddM(2)
+		cpu.jumpOffset(ib)
 	}
 }
 
 // JumpNN loads the 16-bit immediate operand into the program counter.
 func (cpu *CPU) JumpNN() {
+	cpu.jump(cpu.memImmediateWord())
+}
+
+// Loads the provided address into the program counter, which takes a machine
+// cycle.
+func (cpu *CPU) jump(addr uint16) {
 	pc := cpu.r.ProgramCounter()
-	*pc = cpu.memImmediateWord()
+	*pc = addr
 
-	cpu.c.AddM(2)
+	cpu.tick()
 }
 
 // JumpNNConditionally loads the 16-bit immediate operand into the program
 // counter if the status of the provided flag matches the provided status.
 func (cpu *CPU) JumpNNConditionally(flag Flag, isSet bool) {
+	nn := cpu.memImmediateWord()
 	if cpu.shouldJump(flag, isSet) {
-		cpu.JumpNN()
-	} else {
-		cpu.r.IncrementProgramCounter(1)
-		cpu.c.AddM(3)
+		cpu.jump(nn)
 	}
 }
 
 // CallNN pushes the program counter onto the stack, then loads the 16-bit
 // immediate operand into the program counter.
 func (cpu *CPU) CallNN() {
+	cpu.call(cpu.memImmediateWord())
+}
+
+// Pushes the program counter onto the stack, then loads the provided address
+// into it.
+func (cpu *CPU) call(addr uint16) {
 	pc := cpu.r.ProgramCounter()
 	cpu.pushWordOntoStack(*pc)
-	cpu.JumpNN()
+	*pc = addr
 }
 
 // CallNNConditionally pushes the program counter onto the stack, then loads the
 // 16-bit immediate operand into the program counter. The condition is that the
 // status of the provided flag must match the provided status.
 func (cpu *CPU) CallNNConditionally(flag Flag, isSet bool) {
+	nn := cpu.memImmediateWord()
 	if cpu.shouldJump(flag, isSet) {
-		cpu.CallNN()
-	} else {
-		cpu.r.IncrementProgramCounter(1)
-		cpu.c.AddM(3)
+		cpu.call(nn)
 	}
 }
 
 // Return loads a word popped from the stack into the program counter.
 func (cpu *CPU) Return() {
-	pc := cpu.r.ProgramCounter()
-	*pc = cpu.popStack()
-
-	cpu.c.AddM(2)
+	cpu.jump(cpu.popStack())
 }
 
 // ReturnConditionally loads a word popped from the stack into the program
 // counter if the status of the provided flag matches the provided status.
+// Checking the condition takes a machine cycle of its own.
 func (cpu *CPU) ReturnConditionally(flag Flag, isSet bool) {
+	cpu.tick()
+
 	if cpu.shouldJump(flag, isSet) {
 		cpu.Return()
-		cpu.c.AddM(1)
-	} else {
-		cpu.r.IncrementProgramCounter(1)
-		cpu.c.AddM(2)
 	}
 }
 
-// ReturnPostInterrupt is a placeholder.
-// TODO: Revisit when emulating interrupts.
+// ReturnPostInterrupt loads a word popped from the stack into the program
+// counter and sets the interrupt master enable flag, without the delay of EI.
 func (cpu *CPU) ReturnPostInterrupt() {
 	cpu.Return()
+	cpu.ime = true
 }
 
 // Restart pushes the program counter onto the stack, then loads the provided
 // value into the program counter.
 func (cpu *CPU) Restart(t uint8) {
-	pc := cpu.r.ProgramCounter()
-	cpu.pushWordOntoStack(*pc)
-	*pc = uint16(t)
-
-	cpu.c.AddM(2)
+	cpu.call(uint16(t))
 }
 
 /**
@@ -1258,7 +1184,7 @@ func rotate8(x uint8, right bool) (result uint8, rotatedBitset bool) {
 
 func (cpu *CPU) rotate8SwapHelper(x *uint8, right bool) {
 	var rotatedBitset bool
-	*x, rotatedBitset = rotate8(*x, right)
+	*x, rotatedBitset = shift8(*x, right)
 
 	carry, _ := cpu.r.IsFlagSet(FlagC)
 	if carry {
@@ -1273,8 +1199,6 @@ func (cpu *CPU) rotate8SwapHelper(x *uint8, right bool) {
 	cpu.r.ResetFlag(FlagH)
 	cpu.r.ResetFlag(FlagN)
 	cpu.r.PutFlag(FlagZ, *x == 0)
-
-	cpu.c.AddM(1)
 }
 
 func (cpu *CPU) rotate8BothHelper(x *uint8, right bool) {
@@ -1285,15 +1209,11 @@ func (cpu *CPU) rotate8BothHelper(x *uint8, right bool) {
 	cpu.r.ResetFlag(FlagH)
 	cpu.r.ResetFlag(FlagN)
 	cpu.r.PutFlag(FlagZ, *x == 0)
-
-	cpu.c.AddM(1)
 }
 
 // RLCA rotates the contents of the accumulator to the left. The MSB becomes the
 // LSB and the carry flag. All other flags are reset.
 func (cpu *CPU) RLCA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.rotate8BothHelper(acc, false)
 	cpu.r.ResetFlag(FlagZ)
@@ -1302,8 +1222,6 @@ func (cpu *CPU) RLCA() {
 // RLA rotates the contents of the accumulator to the left. The MSB becomes the
 // carry flag and the carry flag becomes the LSB. All other flags are reset.
 func (cpu *CPU) RLA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.rotate8SwapHelper(acc, false)
 	cpu.r.ResetFlag(FlagZ)
@@ -1312,8 +1230,6 @@ func (cpu *CPU) RLA() {
 // RRCA rotates the contents of the accumulator to the right. The LSB becomes
 // the MSB and the carry flag. All other flags are reset.
 func (cpu *CPU) RRCA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.rotate8BothHelper(acc, true)
 	cpu.r.ResetFlag(FlagZ)
@@ -1322,8 +1238,6 @@ func (cpu *CPU) RRCA() {
 // RRA rotates the contents of the accumulator to the right. The LSB becomes the
 // carry flag and the carry flag becomes the MSB. All other flags are reset.
 func (cpu *CPU) RRA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.rotate8SwapHelper(acc, true)
 	cpu.r.ResetFlag(FlagZ)
@@ -1332,8 +1246,6 @@ func (cpu *CPU) RRA() {
 // RLCACB rotates the contents of the accumulator to the left. The MSB becomes
 // the LSB and the carry flag. Flags are updated accordingly.
 func (cpu *CPU) RLCACB() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.rotate8BothHelper(acc, false)
 }
@@ -1341,8 +1253,6 @@ func (cpu *CPU) RLCACB() {
 // RLC rotates the contents of the provided register to the left. The MSB
 // becomes the LSB and the carry flag. Flags are updated accordingly.
 func (cpu *CPU) RLC(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.rotate8BothHelper(a, false)
 }
@@ -1351,8 +1261,6 @@ func (cpu *CPU) RLC(r Register) {
 // to the left. The MSB becomes the the LSB and the carry flag. Flags are
 // updated accordingly.
 func (cpu *CPU) RLCHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.rotate8BothHelper(&val, false)
@@ -1362,8 +1270,6 @@ func (cpu *CPU) RLCHL() {
 // RRCACB rotates the contents of the accumulator to the right. The LSB becomes
 // the MSB and the carry flag. Flags are updated accordingly.
 func (cpu *CPU) RRCACB() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.rotate8BothHelper(acc, true)
 }
@@ -1371,8 +1277,6 @@ func (cpu *CPU) RRCACB() {
 // RRC rotates the contents of the provided register to the right. The LSB
 // becomes the MSB and the carry flag. Flags are updated accordingly.
 func (cpu *CPU) RRC(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.rotate8BothHelper(a, true)
 }
@@ -1381,8 +1285,6 @@ func (cpu *CPU) RRC(r Register) {
 // to the right. The LSB becomes the the MSB and the carry flag. Flags are
 // updated accordingly.
 func (cpu *CPU) RRCHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.rotate8BothHelper(&val, true)
@@ -1393,8 +1295,6 @@ func (cpu *CPU) RRCHL() {
 // the carry flag and the carry flag becomes the LSB. Flags are updated
 // accordingly.
 func (cpu *CPU) RLACB() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.rotate8SwapHelper(acc, false)
 }
@@ -1403,8 +1303,6 @@ func (cpu *CPU) RLACB() {
 // the carry flag and the carry flag becomes the LSB. Flags are updated
 // accordingly.
 func (cpu *CPU) RL(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.rotate8SwapHelper(a, false)
 }
@@ -1413,8 +1311,6 @@ func (cpu *CPU) RL(r Register) {
 // to the left. The MSB becomes the carry flag and the carry flag becomes the
 // LSB. Flags are updated accordingly.
 func (cpu *CPU) RLHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.rotate8SwapHelper(&val, false)
@@ -1425,8 +1321,6 @@ func (cpu *CPU) RLHL() {
 // the carry flag and the carry flag becomes the MSB. Flags are updated
 // accordingly.
 func (cpu *CPU) RRACB() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.rotate8SwapHelper(acc, true)
 }
@@ -1435,8 +1329,6 @@ func (cpu *CPU) RRACB() {
 // becomes the carry flag and the carry flag becomes the MSB. Flags are updated
 // accordingly.
 func (cpu *CPU) RR(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.rotate8SwapHelper(a, true)
 }
@@ -1445,8 +1337,6 @@ func (cpu *CPU) RR(r Register) {
 // to the right. The LSB becomes the carry flag and the carry flag becomes the
 // MSB. Flags are updated accordingly.
 func (cpu *CPU) RRHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.rotate8SwapHelper(&val, true)
@@ -1473,15 +1363,11 @@ func (cpu *CPU) shift8Helper(x *uint8, right bool) {
 	cpu.r.ResetFlag(FlagH)
 	cpu.r.ResetFlag(FlagN)
 	cpu.r.PutFlag(FlagZ, *x == 0)
-
-	cpu.c.AddM(1)
 }
 
 // SLAA shifts the contents of the accumulator to the left. The MSB becomes the
 // carry flag and the LSB is reset. Flags are updated accordingly.
 func (cpu *CPU) SLAA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.shift8Helper(acc, false)
 }
@@ -1489,8 +1375,6 @@ func (cpu *CPU) SLAA() {
 // SLA shifts the contents of the provided register to the left. The MSB becomes
 // the carry flag and the LSB is reset. Flags are updated accordingly.
 func (cpu *CPU) SLA(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.shift8Helper(a, false)
 }
@@ -1499,8 +1383,6 @@ func (cpu *CPU) SLA(r Register) {
 // to the left. The MSB becomes the carry flag and the LSB is reset. Flags are
 // updated accordingly.
 func (cpu *CPU) SLAHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.shift8Helper(&val, false)
@@ -1510,8 +1392,6 @@ func (cpu *CPU) SLAHL() {
 // SRAA shifts the contents of the accumulator to the right. The LSB becomes the
 // carry flag and the MSB retains its value. Flags are updated accordingly.
 func (cpu *CPU) SRAA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	bit7mask := *acc & 0x80
 	cpu.shift8Helper(acc, true)
@@ -1522,8 +1402,6 @@ func (cpu *CPU) SRAA() {
 // becomes the carry flag and the MSB retains its value. Flags are updated
 // accordingly.
 func (cpu *CPU) SRA(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	bit7mask := *a & 0x80
 	cpu.shift8Helper(a, true)
@@ -1534,8 +1412,6 @@ func (cpu *CPU) SRA(r Register) {
 // to the right. The LSB becomes the carry flag and the MSB retains its value.
 // Flags are updated accordingly.
 func (cpu *CPU) SRAHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	bit7mask := val & 0x80
@@ -1547,8 +1423,6 @@ func (cpu *CPU) SRAHL() {
 // SRLA shifts the contents of the accumulator to the right. The LSB becomes the
 // carry flag and the MSB is reset. Flags are updated accordingly.
 func (cpu *CPU) SRLA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.shift8Helper(acc, true)
 }
@@ -1556,8 +1430,6 @@ func (cpu *CPU) SRLA() {
 // SRL shifts the contents of the provided register to the right. The LSB
 // becomes the carry flag and the MSB is reset. Flags are updated accordingly.
 func (cpu *CPU) SRL(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.shift8Helper(a, true)
 }
@@ -1566,8 +1438,6 @@ func (cpu *CPU) SRL(r Register) {
 // to the right. The LSB becomes the carry flag and the MSB is reset. Flags are
 // updated accordingly.
 func (cpu *CPU) SRLHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.shift8Helper(&val, true)
@@ -1585,22 +1455,16 @@ func (cpu *CPU) swap8Helper(x *uint8) {
 	cpu.r.ResetFlag(FlagH)
 	cpu.r.ResetFlag(FlagN)
 	cpu.r.PutFlag(FlagZ, *x == 0)
-
-	cpu.c.AddM(1)
 }
 
 // SwapA swaps the accumulator nibbles. Flags are updated accordingly.
 func (cpu *CPU) SwapA() {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.swap8Helper(acc)
 }
 
 // Swap swaps the provided register's nibbles. Flags are updated accordingly.
 func (cpu *CPU) Swap(r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.swap8Helper(a)
 }
@@ -1608,8 +1472,6 @@ func (cpu *CPU) Swap(r Register) {
 // SwapHL swaps the nibbles of the contents of the memory location referenced by
 // register HL. Flags are updated accordingly.
 func (cpu *CPU) SwapHL() {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.swap8Helper(&val)
@@ -1622,15 +1484,11 @@ func (cpu *CPU) bit8(x, b uint8) {
 	cpu.r.SetFlag(FlagH)
 	cpu.r.ResetFlag(FlagN)
 	cpu.r.PutFlag(FlagZ, !isSet)
-
-	cpu.c.AddM(1)
 }
 
 // BitA sets the Z flag to the complement of the contents of the provided bit in
 // the accumulator. The H and N flags are set and reset respectively.
 func (cpu *CPU) BitA(b uint8) {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.bit8(*acc, b)
 }
@@ -1638,8 +1496,6 @@ func (cpu *CPU) BitA(b uint8) {
 // Bit sets the Z flag to the complement of the contents of the provided bit in
 // the provided register. The H and N flags are set and reset respectively.
 func (cpu *CPU) Bit(b uint8, r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.bit8(*a, b)
 }
@@ -1648,8 +1504,6 @@ func (cpu *CPU) Bit(b uint8, r Register) {
 // in the contents of the memory location referenced by register HL. The H and
 // N flags are set and reset respectively.
 func (cpu *CPU) BitHL(b uint8) {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.bit8(val, b)
@@ -1657,22 +1511,16 @@ func (cpu *CPU) BitHL(b uint8) {
 
 func (cpu *CPU) reset8(x *uint8, b uint8) {
 	*x &^= (1 << b)
-
-	cpu.c.AddM(1)
 }
 
 // ResetA resets the provided bit in the accumulator.
 func (cpu *CPU) ResetA(b uint8) {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.reset8(acc, b)
 }
 
 // Reset resets the provided bit in the provided register.
 func (cpu *CPU) Reset(b uint8, r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.reset8(a, b)
 }
@@ -1680,8 +1528,6 @@ func (cpu *CPU) Reset(b uint8, r Register) {
 // ResetHL resets the provided bit in the contents of the memory location
 // referenced by register HL.
 func (cpu *CPU) ResetHL(b uint8) {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.reset8(&val, b)
@@ -1690,22 +1536,16 @@ func (cpu *CPU) ResetHL(b uint8) {
 
 func (cpu *CPU) set8(x *uint8, b uint8) {
 	*x |= (1 << b)
-
-	cpu.c.AddM(1)
 }
 
 // SetA sets the provided bit in the accumulator.
 func (cpu *CPU) SetA(b uint8) {
-	cpu.r.IncrementProgramCounter(1)
-
 	acc := cpu.r.Accumulator()
 	cpu.set8(acc, b)
 }
 
 // Set sets the provided bit in the provided register.
 func (cpu *CPU) Set(b uint8, r Register) {
-	cpu.r.IncrementProgramCounter(1)
-
 	a, _ := cpu.r.Auxiliary(r)
 	cpu.set8(a, b)
 }
@@ -1713,8 +1553,6 @@ func (cpu *CPU) Set(b uint8, r Register) {
 // SetHL sets the provided bit in the contents of the memory location referenced
 // by register HL.
 func (cpu *CPU) SetHL(b uint8) {
-	cpu.r.IncrementProgramCounter(1)
-
 	hl, _ := cpu.r.Paired(RegisterHL)
 	val := cpu.memByte(hl)
 	cpu.set8(&val, b)
@@ -1737,28 +1575,33 @@ func (cpu *CPU) decrementRegister(r Register) {
 	cpu.r.DecrementPaired(r)
 }
 
-func (cpu *CPU) memByte(addr uint16) uint8 {
-	val := cpu.mmu.Load(addr)
-
+// Advances the rest of the system by one machine cycle. Every machine cycle of
+// an instruction goes through here, whether memory is accessed during it or
+// not.
+func (cpu *CPU) tick() {
 	cpu.c.AddM(1)
+	cpu.bus.Tick()
+}
 
-	return val
+func (cpu *CPU) memByte(addr uint16) uint8 {
+	cpu.tick()
+
+	return cpu.bus.Load(addr)
 }
 
 func (cpu *CPU) memImmediateByte() uint8 {
 	pc := cpu.r.ProgramCounter()
-	val := cpu.mmu.Load(*pc)
+	val := cpu.memByte(*pc)
 
 	*pc++
-	cpu.c.AddM(1)
 
 	return val
 }
 
 func (cpu *CPU) memStoreByte(addr uint16, b uint8) {
-	cpu.mmu.Store(addr, b)
+	cpu.tick()
 
-	cpu.c.AddM(1)
+	cpu.bus.Store(addr, b)
 }
 
 func (cpu *CPU) memWord(addr uint16) uint16 {
@@ -1 +1,319 @@
 package cpu
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/loizoskounios/game-boy-emulator/mmu"
+	"github.com/loizoskounios/game-boy-emulator/timer"
+)
+
+func TestSkipBoot(t *testing.T) {
+	var testCases = []struct {
+		model mmu.Model
+		af    uint16
+		bc    uint16
+		de    uint16
+		hl    uint16
+	}{
+		{mmu.ModelDMG, 0x01B0, 0x0013, 0x00D8, 0x014D},
+		{mmu.ModelMGB, 0xFFB0, 0x0013, 0x00D8, 0x014D},
+		{mmu.ModelSGB, 0x0100, 0x0014, 0x0000, 0xC060},
+		{mmu.ModelCGB, 0x1180, 0x0000, 0xFF56, 0x000D},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("model=%s", tc.model), func(t *testing.T) {
+			cpu := New(SkipBoot(tc.model))
+
+			if af := cpu.r.AF(); af != tc.af {
+				t.Errorf("AF: got 0x%04X, expected 0x%04X", af, tc.af)
+			}
+
+			for _, rr := range []struct {
+				r   Register
+				exp uint16
+			}{{RegisterBC, tc.bc}, {RegisterDE, tc.de}, {RegisterHL, tc.hl}} {
+				if val, _ := cpu.r.Paired(rr.r); val != rr.exp {
+					t.Errorf("%s: got 0x%04X, expected 0x%04X", rr.r, val, rr.exp)
+				}
+			}
+
+			if sp := *cpu.r.StackPointer(); sp != 0xFFFE {
+				t.Errorf("SP: got 0x%04X, expected 0xFFFE", sp)
+			}
+
+			if pc := *cpu.r.ProgramCounter(); pc != 0x0100 {
+				t.Errorf("PC: got 0x%04X, expected 0x0100", pc)
+			}
+
+			if cpu.mmu.BootROMMapped() {
+				t.Error("got boot ROM mapped, expected unmapped")
+			}
+
+			if lcdc := cpu.mmu.Load(0xFF40); lcdc != 0x91 {
+				t.Errorf("LCDC: got 0x%02X, expected 0x91", lcdc)
+			}
+		})
+	}
+}
+
+func TestServiceInterrupt(t *testing.T) {
+	var testCases = []struct {
+		ime     bool
+		enable  uint8
+		flag    uint8
+		pc      uint16
+		cycles  uint64
+		flagOut uint8
+	}{
+		{false, 0x1F, 0x1F, 0xC123, 0, 0x1F},
+		{true, 0x00, 0x1F, 0xC123, 0, 0x1F},
+		{true, 0x1F, 0x01, 0x0040, 5, 0x00},
+		{true, 0x1F, 0x06, 0x0048, 5, 0x04},
+		{true, 0x14, 0x1C, 0x0050, 5, 0x18},
+		{true, 0x18, 0x18, 0x0058, 5, 0x10},
+		{true, 0x10, 0x1F, 0x0060, 5, 0x0F},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("ime=%t IE=0x%02X IF=0x%02X", tc.ime, tc.enable, tc.flag), func(t *testing.T) {
+			cpu := New()
+			cpu.ime = tc.ime
+			*cpu.r.ProgramCounter() = 0xC123
+			*cpu.r.StackPointer() = 0xD000
+			cpu.mmu.Store(0xFFFF, tc.enable)
+			cpu.mmu.Store(0xFF0F, tc.flag)
+
+			serviced := cpu.serviceInterrupt()
+			if serviced != (tc.cycles != 0) {
+				t.Errorf("serviced: got %t, expected %t", serviced, tc.cycles != 0)
+			}
+
+			if pc := *cpu.r.ProgramCounter(); pc != tc.pc {
+				t.Errorf("PC: got 0x%04X, expected 0x%04X", pc, tc.pc)
+			}
+
+			if m := cpu.c.M(); m != tc.cycles {
+				t.Errorf("cycles: got %d, expected %d", m, tc.cycles)
+			}
+
+			if flag := cpu.mmu.Load(0xFF0F); flag != 0xE0|tc.flagOut {
+				t.Errorf("IF: got 0x%02X, expected 0x%02X", flag, 0xE0|tc.flagOut)
+			}
+
+			if !serviced {
+				return
+			}
+
+			if cpu.ime {
+				t.Error("got IME set, expected reset")
+			}
+
+			if ret := cpu.memWord(0xCFFE); ret != 0xC123 {
+				t.Errorf("return address: got 0x%04X, expected 0xC123", ret)
+			}
+		})
+	}
+}
+
+func TestServiceInterruptCancelled(t *testing.T) {
+	cpu := New()
+	cpu.ime = true
+	*cpu.r.ProgramCounter() = 0x1234
+	// Pushing the upper byte of the program counter overwrites IE.
+	*cpu.r.StackPointer() = 0x0000
+	cpu.mmu.Store(0xFFFF, 0x01)
+	cpu.mmu.Store(0xFF0F, 0x01)
+
+	cpu.serviceInterrupt()
+
+	if pc := *cpu.r.ProgramCounter(); pc != 0x0000 {
+		t.Errorf("PC: got 0x%04X, expected 0x0000", pc)
+	}
+	if flag := cpu.mmu.Load(0xFF0F); flag != 0xE1 {
+		t.Errorf("IF: got 0x%02X, expected 0xE1", flag)
+	}
+}
+
+func TestEnableInterrupts(t *testing.T) {
+	cpu := New()
+
+	cpu.EnableInterrupts()
+	cpu.updateIME()
+	if cpu.ime {
+		t.Error("after EI: got IME set, expected reset")
+	}
+
+	cpu.Nop()
+	cpu.updateIME()
+	if !cpu.ime {
+		t.Error("after the next instruction: got IME reset, expected set")
+	}
+
+	// DI right after EI cancels it.
+	cpu = New()
+	cpu.EnableInterrupts()
+	cpu.updateIME()
+	cpu.DisableInterrupts()
+	cpu.updateIME()
+	cpu.Nop()
+	cpu.updateIME()
+	if cpu.ime {
+		t.Error("after EI, DI: got IME set, expected reset")
+	}
+
+	// RETI sets IME immediately.
+	cpu = New()
+	*cpu.r.StackPointer() = 0xD000
+	cpu.ReturnPostInterrupt()
+	if !cpu.ime {
+		t.Error("after RETI: got IME reset, expected set")
+	}
+}
+
+func TestRst(t *testing.T) {
+	for _, skipBoot := range []bool{false, true} {
+		t.Run(fmt.Sprintf("skipBoot=%t", skipBoot), func(t *testing.T) {
+			var cpu *CPU
+			if skipBoot {
+				cpu = New(SkipBoot(mmu.ModelDMG))
+			} else {
+				cpu = New()
+			}
+			expected := *cpu.r.ProgramCounter()
+
+			// INC A, EI, HALT
+			for i, b := range []uint8{0x3C, 0xFB, 0x76} {
+				cpu.mmu.Store(0xC000+uint16(i), b)
+			}
+			*cpu.r.ProgramCounter() = 0xC000
+			cpu.RunFor(10)
+
+			cpu.Rst(true)
+
+			if pc := *cpu.r.ProgramCounter(); pc != expected {
+				t.Errorf("PC: got 0x%04X, expected 0x%04X", pc, expected)
+			}
+			if m := cpu.c.M(); m != 0 {
+				t.Errorf("cycles: got %d, expected 0", m)
+			}
+			if cpu.ime || cpu.halted {
+				t.Errorf("got IME=%t halted=%t, expected both reset", cpu.ime, cpu.halted)
+			}
+			if b := cpu.mmu.Load(0xC000); b != 0x00 {
+				t.Errorf("WRAM: got 0x%02X, expected 0x00", b)
+			}
+			if mapped := cpu.mmu.BootROMMapped(); mapped != !skipBoot {
+				t.Errorf("boot ROM mapped: got %t, expected %t", mapped, !skipBoot)
+			}
+		})
+	}
+}
+
+func TestLoadNN(t *testing.T) {
+	// LD (0xC100),A; LD A,(0xC101)
+	cpu := newProgram(0xEA, 0x00, 0xC1, 0xFA, 0x01, 0xC1)
+	*cpu.r.Accumulator() = 0x12
+	cpu.mmu.Store(0xC101, 0x34)
+
+	cpu.Step()
+	if b := cpu.mmu.Load(0xC100); b != 0x12 {
+		t.Errorf("(0xC100): got 0x%02X, expected 0x12", b)
+	}
+
+	cpu.Step()
+	if a := *cpu.r.Accumulator(); a != 0x34 {
+		t.Errorf("A: got 0x%02X, expected 0x34", a)
+	}
+}
+
+func TestJumpOffsetFlags(t *testing.T) {
+	for _, flags := range []uint8{0x00, 0xF0} {
+		t.Run(fmt.Sprintf("F=0x%02X", flags), func(t *testing.T) {
+			// JR -2
+			cpu := newProgram(0x18, 0xFE)
+			cpu.r.SetAF(uint16(flags))
+
+			cpu.Step()
+			if f := uint8(cpu.r.AF()); f != flags {
+				t.Errorf("got 0x%02X, expected 0x%02X", f, flags)
+			}
+		})
+	}
+}
+
+func TestControlFlow(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		program []uint8
+		pc, sp  uint16
+	}{
+		{"JR", []uint8{0x18, 0x10}, 0xC012, 0xD000},
+		{"JR backwards", []uint8{0x18, 0xFE}, 0xC000, 0xD000},
+		{"JR Z taken", []uint8{0x28, 0x10}, 0xC012, 0xD000},
+		{"JR NZ not taken", []uint8{0x20, 0x10}, 0xC002, 0xD000},
+		{"JP", []uint8{0xC3, 0x34, 0x12}, 0x1234, 0xD000},
+		{"JP NZ not taken", []uint8{0xC2, 0x34, 0x12}, 0xC003, 0xD000},
+		{"CALL", []uint8{0xCD, 0x34, 0x12}, 0x1234, 0xCFFE},
+		{"CALL Z taken", []uint8{0xCC, 0x34, 0x12}, 0x1234, 0xCFFE},
+		{"CALL NZ not taken", []uint8{0xC4, 0x34, 0x12}, 0xC003, 0xD000},
+		{"RST 38H", []uint8{0xFF}, 0x0038, 0xCFFE},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s program=% X", tc.name, tc.program), func(t *testing.T) {
+			cpu := newProgram(tc.program...)
+			cpu.r.SetAF(0x80)
+
+			cpu.Step()
+			if pc := *cpu.r.ProgramCounter(); pc != tc.pc {
+				t.Errorf("PC: got 0x%04X, expected 0x%04X", pc, tc.pc)
+			}
+			if sp := *cpu.r.StackPointer(); sp != tc.sp {
+				t.Errorf("SP: got 0x%04X, expected 0x%04X", sp, tc.sp)
+			}
+
+			// Calls push the address of the instruction following them.
+			if tc.sp == 0xD000 {
+				return
+			}
+			ret := 0xC000 + uint16(len(tc.program))
+			if w := cpu.memWord(tc.sp); w != ret {
+				t.Errorf("return address: got 0x%04X, expected 0x%04X", w, ret)
+			}
+		})
+	}
+}
+
+func TestBusTiming(t *testing.T) {
+	// With TIMA counting every 4 machine cycles from a reset counter, TIMA
+	// reads as 1 from the 4th machine cycle of an instruction onwards.
+	var testCases = []struct {
+		program  []uint8
+		expected uint8
+	}{
+		// LDH A,(TIMA): read during the 3rd machine cycle.
+		{[]uint8{0xF0, 0x05}, 0},
+		// LD A,(TIMA): read during the 4th machine cycle.
+		{[]uint8{0xFA, 0x05, 0xFF}, 1},
+		// LD HL,TIMA; LD A,(HL): read during the 2nd machine cycle.
+		{[]uint8{0x21, 0x05, 0xFF, 0x7E}, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("program=% X", tc.program), func(t *testing.T) {
+			cpu := newProgram(tc.program...)
+			cpu.mmu.Store(timer.AddressTAC, 0x05)
+			cpu.mmu.Timer().ResetDIV()
+
+			for *cpu.r.ProgramCounter() < 0xC000+uint16(len(tc.program)) {
+				cpu.Step()
+			}
+
+			if a := *cpu.r.Accumulator(); a != tc.expected {
+				t.Errorf("got %d, expected %d", a, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package cpu
+
+// Halt suspends the CPU until an enabled interrupt is requested, whether
+// interrupts are enabled or not. If interrupts are disabled and one is already
+// pending, the CPU does not halt, and fails to increment the program counter
+// past the next opcode instead. When HALT directly follows EI, the interrupt
+// is serviced right after it, and returns to HALT, which is executed again.
+func (cpu *CPU) Halt() {
+	if _, ok := cpu.mmu.Interrupts().Pending(); ok && !cpu.ime {
+		if cpu.imeDelay > 0 {
+			*cpu.r.ProgramCounter()--
+			return
+		}
+
+		cpu.haltBug = true
+		return
+	}
+
+	cpu.halted = true
+}
+
+// Halted returns whether the CPU is suspended by HALT.
+func (cpu *CPU) Halted() bool {
+	return cpu.halted
+}
+
+// Returns whether an enabled interrupt has been requested, waking the CPU up
+// from HALT.
+func (cpu *CPU) wake() bool {
+	if _, ok := cpu.mmu.Interrupts().Pending(); !ok {
+		return false
+	}
+
+	cpu.halted = false
+
+	return true
+}
+
+// Stop puts the system in low-power mode, stopping the system clock until a
+// button is pressed. The internal counter of the timer is reset. The byte
+// following the opcode is fetched and discarded.
+func (cpu *CPU) Stop() {
+	cpu.memImmediateByte()
+
+	cpu.mmu.Timer().ResetDIV()
+	cpu.stopped = !cpu.mmu.Joypad().Active()
+}
+
+// Stopped returns whether the system is in low-power mode entered through
+// STOP.
+func (cpu *CPU) Stopped() bool {
+	return cpu.stopped
+}
+
+// Waits for a machine cycle in low-power mode, leaving it if a button is
+// pressed. The CPU clock keeps counting so that time keeps passing for the
+// host, but the rest of the system is not advanced.
+func (cpu *CPU) idleStopped() {
+	if cpu.mmu.Joypad().Active() {
+		cpu.stopped = false
+		return
+	}
+
+	cpu.c.AddM(1)
+}
@@ -0,0 +1,147 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/loizoskounios/game-boy-emulator/interrupts"
+	"github.com/loizoskounios/game-boy-emulator/joypad"
+	"github.com/loizoskounios/game-boy-emulator/timer"
+)
+
+// Returns a CPU about to execute the provided program, stored in working RAM.
+func newProgram(program ...uint8) *CPU {
+	cpu := New()
+	for i, b := range program {
+		cpu.mmu.Store(0xC000+uint16(i), b)
+	}
+	*cpu.r.ProgramCounter() = 0xC000
+	*cpu.r.StackPointer() = 0xD000
+
+	return cpu
+}
+
+func TestHalt(t *testing.T) {
+	// HALT, INC A
+	cpu := newProgram(0x76, 0x3C)
+	cpu.mmu.Store(interrupts.AddressIE, 0x04)
+
+	for i := 0; i < 4; i++ {
+		cpu.step()
+	}
+	if !cpu.Halted() {
+		t.Fatal("got running, expected halted")
+	}
+	if m := cpu.c.M(); m != 4 {
+		t.Errorf("cycles: got %d, expected 4", m)
+	}
+
+	// With interrupts disabled, the CPU resumes after HALT without servicing
+	// the interrupt.
+	cpu.mmu.Interrupts().Request(interrupts.Timer)
+	cpu.step()
+	if cpu.Halted() {
+		t.Error("got halted, expected running")
+	}
+	if a := *cpu.r.Accumulator(); a != 1 {
+		t.Errorf("A: got %d, expected 1", a)
+	}
+	if pc := *cpu.r.ProgramCounter(); pc != 0xC002 {
+		t.Errorf("PC: got 0x%04X, expected 0xC002", pc)
+	}
+}
+
+func TestHaltInterrupt(t *testing.T) {
+	cpu := newProgram(0x76)
+	cpu.ime = true
+	cpu.mmu.Store(interrupts.AddressIE, 0x04)
+
+	cpu.step()
+	cpu.step()
+	cpu.mmu.Interrupts().Request(interrupts.Timer)
+	cpu.step()
+
+	if pc := *cpu.r.ProgramCounter(); pc != interrupts.Timer.Vector() {
+		t.Errorf("PC: got 0x%04X, expected 0x%04X", pc, interrupts.Timer.Vector())
+	}
+	if ret := cpu.memWord(0xCFFE); ret != 0xC001 {
+		t.Errorf("return address: got 0x%04X, expected 0xC001", ret)
+	}
+}
+
+func TestHaltBug(t *testing.T) {
+	// HALT, INC A
+	cpu := newProgram(0x76, 0x3C)
+	cpu.mmu.Store(interrupts.AddressIE, 0x04)
+	cpu.mmu.Interrupts().Request(interrupts.Timer)
+
+	for i := 0; i < 3; i++ {
+		cpu.step()
+	}
+
+	if cpu.Halted() {
+		t.Error("got halted, expected running")
+	}
+	if a := *cpu.r.Accumulator(); a != 2 {
+		t.Errorf("A: got %d, expected 2", a)
+	}
+	if pc := *cpu.r.ProgramCounter(); pc != 0xC002 {
+		t.Errorf("PC: got 0x%04X, expected 0xC002", pc)
+	}
+}
+
+func TestHaltAfterEI(t *testing.T) {
+	// EI, HALT, INC A
+	cpu := newProgram(0xFB, 0x76, 0x3C)
+	cpu.mmu.Store(interrupts.AddressIE, 0x04)
+	cpu.mmu.Interrupts().Request(interrupts.Timer)
+
+	for i := 0; i < 3; i++ {
+		cpu.step()
+	}
+
+	if pc := *cpu.r.ProgramCounter(); pc != interrupts.Timer.Vector() {
+		t.Errorf("PC: got 0x%04X, expected 0x%04X", pc, interrupts.Timer.Vector())
+	}
+	if ret := cpu.memWord(0xCFFE); ret != 0xC001 {
+		t.Errorf("return address: got 0x%04X, expected 0xC001", ret)
+	}
+	if cpu.haltBug {
+		t.Error("got HALT bug, expected none")
+	}
+	if a := *cpu.r.Accumulator(); a != 0 {
+		t.Errorf("A: got %d, expected 0", a)
+	}
+}
+
+func TestStop(t *testing.T) {
+	// STOP 0, INC A
+	cpu := newProgram(0x10, 0x00, 0x3C)
+	cpu.mmu.Store(joypad.AddressP1, 0x00)
+	cpu.mmu.Timer().SetDIV(0xAB)
+
+	cpu.step()
+	if !cpu.Stopped() {
+		t.Fatal("got running, expected stopped")
+	}
+	if div := cpu.mmu.Load(timer.AddressDIV); div != 0 {
+		t.Errorf("DIV: got 0x%02X, expected 0x00", div)
+	}
+
+	// The system clock is stopped, so DIV does not move.
+	for i := 0; i < 1000; i++ {
+		cpu.step()
+	}
+	if div := cpu.mmu.Load(timer.AddressDIV); div != 0 {
+		t.Errorf("DIV: got 0x%02X, expected 0x00", div)
+	}
+
+	cpu.mmu.Joypad().Press(joypad.Start)
+	cpu.step()
+	cpu.step()
+	if cpu.Stopped() {
+		t.Error("got stopped, expected running")
+	}
+	if a := *cpu.r.Accumulator(); a != 1 {
+		t.Errorf("A: got %d, expected 1", a)
+	}
+}
@@ -0,0 +1,28 @@
+package cpu
+
+import "fmt"
+
+// IllegalOpcodeError is reported when the CPU runs into one of the opcodes
+// that do not map to an instruction. The CPU locks up until it is reset, as
+// the hardware does.
+type IllegalOpcodeError struct {
+	Opcode uint8
+	PC     uint16
+}
+
+func (e *IllegalOpcodeError) Error() string {
+	return fmt.Sprintf("illegal opcode 0x%02X at PC=0x%04X", e.Opcode, e.PC)
+}
+
+// Illegal locks up the CPU after fetching the provided illegal opcode. The
+// program counter is left pointing at the opcode.
+func (cpu *CPU) Illegal(opcode uint8) {
+	*cpu.r.ProgramCounter() = cpu.opcodeAddr
+
+	cpu.err = &IllegalOpcodeError{Opcode: opcode, PC: cpu.opcodeAddr}
+}
+
+// Err returns the error that locked up the CPU, or nil if it is running.
+func (cpu *CPU) Err() error {
+	return cpu.err
+}
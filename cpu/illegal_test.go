@@ -0,0 +1,64 @@
+package cpu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/loizoskounios/game-boy-emulator/interrupts"
+)
+
+func TestIllegal(t *testing.T) {
+	// INC A, 0xDD, INC A
+	cpu := newProgram(0x3C, 0xDD, 0x3C)
+	cpu.ime = true
+	cpu.mmu.Store(interrupts.AddressIE, 0x1F)
+
+	for i := 0; i < 4; i++ {
+		cpu.step()
+	}
+
+	var illegal *IllegalOpcodeError
+	if !errors.As(cpu.Err(), &illegal) {
+		t.Fatalf("got %v, expected *IllegalOpcodeError", cpu.Err())
+	}
+	if illegal.Opcode != 0xDD || illegal.PC != 0xC001 {
+		t.Errorf("got opcode 0x%02X at 0x%04X, expected 0xDD at 0xC001", illegal.Opcode, illegal.PC)
+	}
+	if msg := illegal.Error(); msg != "illegal opcode 0xDD at PC=0xC001" {
+		t.Errorf("got %q", msg)
+	}
+
+	// Neither instructions nor interrupts are executed once locked up.
+	cpu.mmu.Interrupts().Request(interrupts.VBlank)
+	cpu.step()
+	if pc := *cpu.r.ProgramCounter(); pc != 0xC001 {
+		t.Errorf("PC: got 0x%04X, expected 0xC001", pc)
+	}
+	if a := *cpu.r.Accumulator(); a != 1 {
+		t.Errorf("A: got %d, expected 1", a)
+	}
+	if m := cpu.c.M(); m != 5 {
+		t.Errorf("cycles: got %d, expected 5", m)
+	}
+}
+
+func TestIllegalAfterHaltBug(t *testing.T) {
+	// HALT, 0xDD
+	cpu := newProgram(0x76, 0xDD)
+	cpu.mmu.Store(interrupts.AddressIE, 0x04)
+	cpu.mmu.Interrupts().Request(interrupts.Timer)
+
+	cpu.step()
+	cpu.step()
+
+	var illegal *IllegalOpcodeError
+	if !errors.As(cpu.Err(), &illegal) {
+		t.Fatalf("got %v, expected *IllegalOpcodeError", cpu.Err())
+	}
+	if illegal.PC != 0xC001 {
+		t.Errorf("got PC=0x%04X, expected 0xC001", illegal.PC)
+	}
+	if pc := *cpu.r.ProgramCounter(); pc != 0xC001 {
+		t.Errorf("PC: got 0x%04X, expected 0xC001", pc)
+	}
+}
@@ -5,6 +5,8 @@ import (
 )
 
 type (
+	// instruction describes an instruction. The machine cycles of
+	// CB-prefixed instructions include those spent on the prefix.
 	instruction struct {
 		opcode        uint8
 		machineCycles uint8
@@ -15,7 +17,11 @@ type (
 	instructionSet [256]*instruction
 )
 
-func (i instruction) String() string {
+func (i *instruction) String() string {
+	if instructionsCB[i.opcode] == i {
+		return fmt.Sprintf("0xCB%02X %s", i.opcode, i.mnemonic)
+	}
+
 	return fmt.Sprintf("0x%02X %s", i.opcode, i.mnemonic)
 }
 
@@ -25,22 +31,22 @@ var instructions = instructionSet{
 	 */
 
 	0x00: &instruction{0x00, 1, "NOP", func(cpu *CPU) { cpu.Nop() }},
-	0x10: &instruction{0x10, 1, "STOP 0", func(cpu *CPU) { cpu.Nop() }},
-	0x76: &instruction{0x76, 1, "HALT", func(cpu *CPU) { cpu.Nop() }},
+	0x10: &instruction{0x10, 2, "STOP 0", func(cpu *CPU) { cpu.Stop() }},
+	0x76: &instruction{0x76, 1, "HALT", func(cpu *CPU) { cpu.Halt() }},
 	0xCB: &instruction{0xCB, 1, "PREFIX CB", func(cpu *CPU) { cpu.CB() }},
-	0xF3: &instruction{0xF3, 1, "DI", func(cpu *CPU) { cpu.Nop() }},
-	0xFB: &instruction{0xFB, 1, "EI", func(cpu *CPU) { cpu.Nop() }},
-	0xD3: &instruction{0xD3, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
-	0xDB: &instruction{0xDB, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
-	0xDD: &instruction{0xDD, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
-	0xE3: &instruction{0xE3, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
-	0xE4: &instruction{0xE4, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
-	0xEB: &instruction{0xEB, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
-	0xEC: &instruction{0xEC, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
-	0xED: &instruction{0xED, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
-	0xF4: &instruction{0xF4, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
-	0xFC: &instruction{0xFC, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
-	0xFD: &instruction{0xFD, 1, "BLANK", func(cpu *CPU) { cpu.Nop() }},
+	0xF3: &instruction{0xF3, 1, "DI", func(cpu *CPU) { cpu.DisableInterrupts() }},
+	0xFB: &instruction{0xFB, 1, "EI", func(cpu *CPU) { cpu.EnableInterrupts() }},
+	0xD3: &instruction{0xD3, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xD3) }},
+	0xDB: &instruction{0xDB, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xDB) }},
+	0xDD: &instruction{0xDD, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xDD) }},
+	0xE3: &instruction{0xE3, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xE3) }},
+	0xE4: &instruction{0xE4, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xE4) }},
+	0xEB: &instruction{0xEB, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xEB) }},
+	0xEC: &instruction{0xEC, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xEC) }},
+	0xED: &instruction{0xED, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xED) }},
+	0xF4: &instruction{0xF4, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xF4) }},
+	0xFC: &instruction{0xFC, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xFC) }},
+	0xFD: &instruction{0xFD, 1, "ILLEGAL", func(cpu *CPU) { cpu.Illegal(0xFD) }},
 
 	/**
 	 * 8-bit loads
@@ -470,386 +476,386 @@ var instructionsCB = instructionSet{
 	 */
 
 	// Register (A, B, C, D, E, H, L) <<
-	0x07: &instruction{0x07, 1, "RLC A", func(cpu *CPU) { cpu.RLCACB() }},
-	0x00: &instruction{0x00, 1, "RLC B", func(cpu *CPU) { cpu.RLC(RegisterB) }},
-	0x01: &instruction{0x01, 1, "RLC C", func(cpu *CPU) { cpu.RLC(RegisterC) }},
-	0x02: &instruction{0x02, 1, "RLC D", func(cpu *CPU) { cpu.RLC(RegisterD) }},
-	0x03: &instruction{0x03, 1, "RLC E", func(cpu *CPU) { cpu.RLC(RegisterE) }},
-	0x04: &instruction{0x04, 1, "RLC H", func(cpu *CPU) { cpu.RLC(RegisterH) }},
-	0x05: &instruction{0x05, 1, "RLC L", func(cpu *CPU) { cpu.RLC(RegisterL) }},
+	0x07: &instruction{0x07, 2, "RLC A", func(cpu *CPU) { cpu.RLCACB() }},
+	0x00: &instruction{0x00, 2, "RLC B", func(cpu *CPU) { cpu.RLC(RegisterB) }},
+	0x01: &instruction{0x01, 2, "RLC C", func(cpu *CPU) { cpu.RLC(RegisterC) }},
+	0x02: &instruction{0x02, 2, "RLC D", func(cpu *CPU) { cpu.RLC(RegisterD) }},
+	0x03: &instruction{0x03, 2, "RLC E", func(cpu *CPU) { cpu.RLC(RegisterE) }},
+	0x04: &instruction{0x04, 2, "RLC H", func(cpu *CPU) { cpu.RLC(RegisterH) }},
+	0x05: &instruction{0x05, 2, "RLC L", func(cpu *CPU) { cpu.RLC(RegisterL) }},
 
 	// Memory[HL] <<
-	0x06: &instruction{0x06, 3, "RLC (HL)", func(cpu *CPU) { cpu.RLCHL() }},
+	0x06: &instruction{0x06, 4, "RLC (HL)", func(cpu *CPU) { cpu.RLCHL() }},
 
 	// Register (A, B, C, D, E, H, L) >>
-	0x0F: &instruction{0x0F, 1, "RRC A", func(cpu *CPU) { cpu.RRCACB() }},
-	0x08: &instruction{0x08, 1, "RRC B", func(cpu *CPU) { cpu.RRC(RegisterB) }},
-	0x09: &instruction{0x09, 1, "RRC C", func(cpu *CPU) { cpu.RRC(RegisterC) }},
-	0x0A: &instruction{0x0A, 1, "RRC D", func(cpu *CPU) { cpu.RRC(RegisterD) }},
-	0x0B: &instruction{0x0B, 1, "RRC E", func(cpu *CPU) { cpu.RRC(RegisterE) }},
-	0x0C: &instruction{0x0C, 1, "RRC H", func(cpu *CPU) { cpu.RRC(RegisterH) }},
-	0x0D: &instruction{0x0D, 1, "RRC L", func(cpu *CPU) { cpu.RRC(RegisterL) }},
+	0x0F: &instruction{0x0F, 2, "RRC A", func(cpu *CPU) { cpu.RRCACB() }},
+	0x08: &instruction{0x08, 2, "RRC B", func(cpu *CPU) { cpu.RRC(RegisterB) }},
+	0x09: &instruction{0x09, 2, "RRC C", func(cpu *CPU) { cpu.RRC(RegisterC) }},
+	0x0A: &instruction{0x0A, 2, "RRC D", func(cpu *CPU) { cpu.RRC(RegisterD) }},
+	0x0B: &instruction{0x0B, 2, "RRC E", func(cpu *CPU) { cpu.RRC(RegisterE) }},
+	0x0C: &instruction{0x0C, 2, "RRC H", func(cpu *CPU) { cpu.RRC(RegisterH) }},
+	0x0D: &instruction{0x0D, 2, "RRC L", func(cpu *CPU) { cpu.RRC(RegisterL) }},
 
 	// Memory[HL] >>
-	0x0E: &instruction{0x0E, 3, "RRC (HL)", func(cpu *CPU) { cpu.RRCHL() }},
+	0x0E: &instruction{0x0E, 4, "RRC (HL)", func(cpu *CPU) { cpu.RRCHL() }},
 
 	// Register (A, B, C, D, E, H, L) <<
-	0x17: &instruction{0x17, 1, "RL A", func(cpu *CPU) { cpu.RLA() }},
-	0x10: &instruction{0x10, 1, "RL B", func(cpu *CPU) { cpu.RL(RegisterB) }},
-	0x11: &instruction{0x11, 1, "RL C", func(cpu *CPU) { cpu.RL(RegisterC) }},
-	0x12: &instruction{0x12, 1, "RL D", func(cpu *CPU) { cpu.RL(RegisterD) }},
-	0x13: &instruction{0x13, 1, "RL E", func(cpu *CPU) { cpu.RL(RegisterE) }},
-	0x14: &instruction{0x14, 1, "RL H", func(cpu *CPU) { cpu.RL(RegisterH) }},
-	0x15: &instruction{0x15, 1, "RL L", func(cpu *CPU) { cpu.RL(RegisterL) }},
+	0x17: &instruction{0x17, 2, "RL A", func(cpu *CPU) { cpu.RLACB() }},
+	0x10: &instruction{0x10, 2, "RL B", func(cpu *CPU) { cpu.RL(RegisterB) }},
+	0x11: &instruction{0x11, 2, "RL C", func(cpu *CPU) { cpu.RL(RegisterC) }},
+	0x12: &instruction{0x12, 2, "RL D", func(cpu *CPU) { cpu.RL(RegisterD) }},
+	0x13: &instruction{0x13, 2, "RL E", func(cpu *CPU) { cpu.RL(RegisterE) }},
+	0x14: &instruction{0x14, 2, "RL H", func(cpu *CPU) { cpu.RL(RegisterH) }},
+	0x15: &instruction{0x15, 2, "RL L", func(cpu *CPU) { cpu.RL(RegisterL) }},
 
 	// Memory[HL] <<
-	0x16: &instruction{0x16, 3, "RL (HL)", func(cpu *CPU) { cpu.RLHL() }},
+	0x16: &instruction{0x16, 4, "RL (HL)", func(cpu *CPU) { cpu.RLHL() }},
 
 	// Register (A, B, C, D, E, H, L) >>
-	0x1F: &instruction{0x1F, 1, "RR A", func(cpu *CPU) { cpu.RRA() }},
-	0x18: &instruction{0x18, 1, "RR B", func(cpu *CPU) { cpu.RR(RegisterB) }},
-	0x19: &instruction{0x19, 1, "RR C", func(cpu *CPU) { cpu.RR(RegisterC) }},
-	0x1A: &instruction{0x1A, 1, "RR D", func(cpu *CPU) { cpu.RR(RegisterD) }},
-	0x1B: &instruction{0x1B, 1, "RR E", func(cpu *CPU) { cpu.RR(RegisterE) }},
-	0x1C: &instruction{0x1C, 1, "RR H", func(cpu *CPU) { cpu.RR(RegisterH) }},
-	0x1D: &instruction{0x1D, 1, "RR L", func(cpu *CPU) { cpu.RR(RegisterL) }},
+	0x1F: &instruction{0x1F, 2, "RR A", func(cpu *CPU) { cpu.RRACB() }},
+	0x18: &instruction{0x18, 2, "RR B", func(cpu *CPU) { cpu.RR(RegisterB) }},
+	0x19: &instruction{0x19, 2, "RR C", func(cpu *CPU) { cpu.RR(RegisterC) }},
+	0x1A: &instruction{0x1A, 2, "RR D", func(cpu *CPU) { cpu.RR(RegisterD) }},
+	0x1B: &instruction{0x1B, 2, "RR E", func(cpu *CPU) { cpu.RR(RegisterE) }},
+	0x1C: &instruction{0x1C, 2, "RR H", func(cpu *CPU) { cpu.RR(RegisterH) }},
+	0x1D: &instruction{0x1D, 2, "RR L", func(cpu *CPU) { cpu.RR(RegisterL) }},
 
 	// Memory[HL] >>
-	0x1E: &instruction{0x1E, 3, "RR (HL)", func(cpu *CPU) { cpu.RRHL() }},
+	0x1E: &instruction{0x1E, 4, "RR (HL)", func(cpu *CPU) { cpu.RRHL() }},
 
 	// Register (A, B, C, D, E, H, L) <<
-	0x27: &instruction{0x27, 1, "SLA A", func(cpu *CPU) { cpu.SLAA() }},
-	0x20: &instruction{0x20, 1, "SLA B", func(cpu *CPU) { cpu.SLA(RegisterB) }},
-	0x21: &instruction{0x21, 1, "SLA C", func(cpu *CPU) { cpu.SLA(RegisterC) }},
-	0x22: &instruction{0x22, 1, "SLA D", func(cpu *CPU) { cpu.SLA(RegisterD) }},
-	0x23: &instruction{0x23, 1, "SLA E", func(cpu *CPU) { cpu.SLA(RegisterE) }},
-	0x24: &instruction{0x24, 1, "SLA H", func(cpu *CPU) { cpu.SLA(RegisterH) }},
-	0x25: &instruction{0x25, 1, "SLA L", func(cpu *CPU) { cpu.SLA(RegisterL) }},
+	0x27: &instruction{0x27, 2, "SLA A", func(cpu *CPU) { cpu.SLAA() }},
+	0x20: &instruction{0x20, 2, "SLA B", func(cpu *CPU) { cpu.SLA(RegisterB) }},
+	0x21: &instruction{0x21, 2, "SLA C", func(cpu *CPU) { cpu.SLA(RegisterC) }},
+	0x22: &instruction{0x22, 2, "SLA D", func(cpu *CPU) { cpu.SLA(RegisterD) }},
+	0x23: &instruction{0x23, 2, "SLA E", func(cpu *CPU) { cpu.SLA(RegisterE) }},
+	0x24: &instruction{0x24, 2, "SLA H", func(cpu *CPU) { cpu.SLA(RegisterH) }},
+	0x25: &instruction{0x25, 2, "SLA L", func(cpu *CPU) { cpu.SLA(RegisterL) }},
 
 	// Memory[HL] <<
-	0x26: &instruction{0x26, 3, "SLA (HL)", func(cpu *CPU) { cpu.SLAHL() }},
+	0x26: &instruction{0x26, 4, "SLA (HL)", func(cpu *CPU) { cpu.SLAHL() }},
 
 	// Register (A, B, C, D, E, H, L) >>
-	0x2F: &instruction{0x2F, 1, "SRA A", func(cpu *CPU) { cpu.SRAA() }},
-	0x28: &instruction{0x28, 1, "SRA B", func(cpu *CPU) { cpu.SRA(RegisterB) }},
-	0x29: &instruction{0x29, 1, "SRA C", func(cpu *CPU) { cpu.SRA(RegisterC) }},
-	0x2A: &instruction{0x2A, 1, "SRA D", func(cpu *CPU) { cpu.SRA(RegisterD) }},
-	0x2B: &instruction{0x2B, 1, "SRA E", func(cpu *CPU) { cpu.SRA(RegisterE) }},
-	0x2C: &instruction{0x2C, 1, "SRA H", func(cpu *CPU) { cpu.SRA(RegisterH) }},
-	0x2D: &instruction{0x2D, 1, "SRA L", func(cpu *CPU) { cpu.SRA(RegisterL) }},
+	0x2F: &instruction{0x2F, 2, "SRA A", func(cpu *CPU) { cpu.SRAA() }},
+	0x28: &instruction{0x28, 2, "SRA B", func(cpu *CPU) { cpu.SRA(RegisterB) }},
+	0x29: &instruction{0x29, 2, "SRA C", func(cpu *CPU) { cpu.SRA(RegisterC) }},
+	0x2A: &instruction{0x2A, 2, "SRA D", func(cpu *CPU) { cpu.SRA(RegisterD) }},
+	0x2B: &instruction{0x2B, 2, "SRA E", func(cpu *CPU) { cpu.SRA(RegisterE) }},
+	0x2C: &instruction{0x2C, 2, "SRA H", func(cpu *CPU) { cpu.SRA(RegisterH) }},
+	0x2D: &instruction{0x2D, 2, "SRA L", func(cpu *CPU) { cpu.SRA(RegisterL) }},
 
 	// Memory[HL] >>
-	0x2E: &instruction{0x2E, 3, "SRA (HL)", func(cpu *CPU) { cpu.SRAHL() }},
+	0x2E: &instruction{0x2E, 4, "SRA (HL)", func(cpu *CPU) { cpu.SRAHL() }},
 
 	// Register (A, B, C, D, E, H, L) >>
-	0x3F: &instruction{0x3F, 1, "SRL A", func(cpu *CPU) { cpu.SRLA() }},
-	0x38: &instruction{0x38, 1, "SRL B", func(cpu *CPU) { cpu.SRL(RegisterB) }},
-	0x39: &instruction{0x39, 1, "SRL C", func(cpu *CPU) { cpu.SRL(RegisterC) }},
-	0x3A: &instruction{0x3A, 1, "SRL D", func(cpu *CPU) { cpu.SRL(RegisterD) }},
-	0x3B: &instruction{0x3B, 1, "SRL E", func(cpu *CPU) { cpu.SRL(RegisterE) }},
-	0x3C: &instruction{0x3C, 1, "SRL H", func(cpu *CPU) { cpu.SRL(RegisterH) }},
-	0x3D: &instruction{0x3D, 1, "SRL L", func(cpu *CPU) { cpu.SRL(RegisterL) }},
+	0x3F: &instruction{0x3F, 2, "SRL A", func(cpu *CPU) { cpu.SRLA() }},
+	0x38: &instruction{0x38, 2, "SRL B", func(cpu *CPU) { cpu.SRL(RegisterB) }},
+	0x39: &instruction{0x39, 2, "SRL C", func(cpu *CPU) { cpu.SRL(RegisterC) }},
+	0x3A: &instruction{0x3A, 2, "SRL D", func(cpu *CPU) { cpu.SRL(RegisterD) }},
+	0x3B: &instruction{0x3B, 2, "SRL E", func(cpu *CPU) { cpu.SRL(RegisterE) }},
+	0x3C: &instruction{0x3C, 2, "SRL H", func(cpu *CPU) { cpu.SRL(RegisterH) }},
+	0x3D: &instruction{0x3D, 2, "SRL L", func(cpu *CPU) { cpu.SRL(RegisterL) }},
 
 	// Memory[HL] >>
-	0x3E: &instruction{0x3E, 3, "SRL (HL)", func(cpu *CPU) { cpu.SRLHL() }},
+	0x3E: &instruction{0x3E, 4, "SRL (HL)", func(cpu *CPU) { cpu.SRLHL() }},
 
 	// Register (A, B, C, D, E, H, L) <- Register (A, B, C, D, E, H, L)[0-4] | Register (A, B, C, D, E, H, L)[4-8]
-	0x37: &instruction{0x37, 1, "SWAP A", func(cpu *CPU) { cpu.SwapA() }},
-	0x30: &instruction{0x30, 1, "SWAP B", func(cpu *CPU) { cpu.Swap(RegisterB) }},
-	0x31: &instruction{0x31, 1, "SWAP C", func(cpu *CPU) { cpu.Swap(RegisterC) }},
-	0x32: &instruction{0x32, 1, "SWAP D", func(cpu *CPU) { cpu.Swap(RegisterD) }},
-	0x33: &instruction{0x33, 1, "SWAP E", func(cpu *CPU) { cpu.Swap(RegisterE) }},
-	0x34: &instruction{0x34, 1, "SWAP H", func(cpu *CPU) { cpu.Swap(RegisterH) }},
-	0x35: &instruction{0x35, 1, "SWAP L", func(cpu *CPU) { cpu.Swap(RegisterL) }},
+	0x37: &instruction{0x37, 2, "SWAP A", func(cpu *CPU) { cpu.SwapA() }},
+	0x30: &instruction{0x30, 2, "SWAP B", func(cpu *CPU) { cpu.Swap(RegisterB) }},
+	0x31: &instruction{0x31, 2, "SWAP C", func(cpu *CPU) { cpu.Swap(RegisterC) }},
+	0x32: &instruction{0x32, 2, "SWAP D", func(cpu *CPU) { cpu.Swap(RegisterD) }},
+	0x33: &instruction{0x33, 2, "SWAP E", func(cpu *CPU) { cpu.Swap(RegisterE) }},
+	0x34: &instruction{0x34, 2, "SWAP H", func(cpu *CPU) { cpu.Swap(RegisterH) }},
+	0x35: &instruction{0x35, 2, "SWAP L", func(cpu *CPU) { cpu.Swap(RegisterL) }},
 
 	// Memory[HL] <- Memory[HL][0-4] | Memory[HL][4-8]
-	0x36: &instruction{0x36, 3, "SWAP (HL)", func(cpu *CPU) { cpu.SwapHL() }},
+	0x36: &instruction{0x36, 4, "SWAP (HL)", func(cpu *CPU) { cpu.SwapHL() }},
 
 	// Flag (Z) <- ^Register (A, B, C, D, E, H, L)[0]
-	0x47: &instruction{0x47, 1, "BIT 0,A", func(cpu *CPU) { cpu.BitA(0) }},
-	0x40: &instruction{0x40, 1, "BIT 0,B", func(cpu *CPU) { cpu.Bit(0, RegisterB) }},
-	0x41: &instruction{0x41, 1, "BIT 0,C", func(cpu *CPU) { cpu.Bit(0, RegisterC) }},
-	0x42: &instruction{0x42, 1, "BIT 0,D", func(cpu *CPU) { cpu.Bit(0, RegisterD) }},
-	0x43: &instruction{0x43, 1, "BIT 0,E", func(cpu *CPU) { cpu.Bit(0, RegisterE) }},
-	0x44: &instruction{0x44, 1, "BIT 0,H", func(cpu *CPU) { cpu.Bit(0, RegisterH) }},
-	0x45: &instruction{0x45, 1, "BIT 0,L", func(cpu *CPU) { cpu.Bit(0, RegisterL) }},
+	0x47: &instruction{0x47, 2, "BIT 0,A", func(cpu *CPU) { cpu.BitA(0) }},
+	0x40: &instruction{0x40, 2, "BIT 0,B", func(cpu *CPU) { cpu.Bit(0, RegisterB) }},
+	0x41: &instruction{0x41, 2, "BIT 0,C", func(cpu *CPU) { cpu.Bit(0, RegisterC) }},
+	0x42: &instruction{0x42, 2, "BIT 0,D", func(cpu *CPU) { cpu.Bit(0, RegisterD) }},
+	0x43: &instruction{0x43, 2, "BIT 0,E", func(cpu *CPU) { cpu.Bit(0, RegisterE) }},
+	0x44: &instruction{0x44, 2, "BIT 0,H", func(cpu *CPU) { cpu.Bit(0, RegisterH) }},
+	0x45: &instruction{0x45, 2, "BIT 0,L", func(cpu *CPU) { cpu.Bit(0, RegisterL) }},
 
 	// Flag (Z) <- ^Memory[HL][0]
-	0x46: &instruction{0x46, 2, "BIT 0,(HL)", func(cpu *CPU) { cpu.BitHL(0) }},
+	0x46: &instruction{0x46, 3, "BIT 0,(HL)", func(cpu *CPU) { cpu.BitHL(0) }},
 
 	// Flag (Z) <- ^Register (A, B, C, D, E, H, L)[1]
-	0x4F: &instruction{0x4F, 1, "BIT 1,A", func(cpu *CPU) { cpu.BitA(1) }},
-	0x48: &instruction{0x48, 1, "BIT 1,B", func(cpu *CPU) { cpu.Bit(1, RegisterB) }},
-	0x49: &instruction{0x49, 1, "BIT 1,C", func(cpu *CPU) { cpu.Bit(1, RegisterC) }},
-	0x4A: &instruction{0x4A, 1, "BIT 1,D", func(cpu *CPU) { cpu.Bit(1, RegisterD) }},
-	0x4B: &instruction{0x4B, 1, "BIT 1,E", func(cpu *CPU) { cpu.Bit(1, RegisterE) }},
-	0x4C: &instruction{0x4C, 1, "BIT 1,H", func(cpu *CPU) { cpu.Bit(1, RegisterH) }},
-	0x4D: &instruction{0x4D, 1, "BIT 1,L", func(cpu *CPU) { cpu.Bit(1, RegisterL) }},
+	0x4F: &instruction{0x4F, 2, "BIT 1,A", func(cpu *CPU) { cpu.BitA(1) }},
+	0x48: &instruction{0x48, 2, "BIT 1,B", func(cpu *CPU) { cpu.Bit(1, RegisterB) }},
+	0x49: &instruction{0x49, 2, "BIT 1,C", func(cpu *CPU) { cpu.Bit(1, RegisterC) }},
+	0x4A: &instruction{0x4A, 2, "BIT 1,D", func(cpu *CPU) { cpu.Bit(1, RegisterD) }},
+	0x4B: &instruction{0x4B, 2, "BIT 1,E", func(cpu *CPU) { cpu.Bit(1, RegisterE) }},
+	0x4C: &instruction{0x4C, 2, "BIT 1,H", func(cpu *CPU) { cpu.Bit(1, RegisterH) }},
+	0x4D: &instruction{0x4D, 2, "BIT 1,L", func(cpu *CPU) { cpu.Bit(1, RegisterL) }},
 
 	// Flag (Z) <- ^Memory[HL][1]
-	0x4E: &instruction{0x4E, 2, "BIT 1,(HL)", func(cpu *CPU) { cpu.BitHL(1) }},
+	0x4E: &instruction{0x4E, 3, "BIT 1,(HL)", func(cpu *CPU) { cpu.BitHL(1) }},
 
 	// Flag (Z) <- ^Register (A, B, C, D, E, H, L)[2]
-	0x57: &instruction{0x57, 1, "BIT 2,A", func(cpu *CPU) { cpu.BitA(2) }},
-	0x50: &instruction{0x50, 1, "BIT 2,B", func(cpu *CPU) { cpu.Bit(2, RegisterB) }},
-	0x51: &instruction{0x51, 1, "BIT 2,C", func(cpu *CPU) { cpu.Bit(2, RegisterC) }},
-	0x52: &instruction{0x52, 1, "BIT 2,D", func(cpu *CPU) { cpu.Bit(2, RegisterD) }},
-	0x53: &instruction{0x53, 1, "BIT 2,E", func(cpu *CPU) { cpu.Bit(2, RegisterE) }},
-	0x54: &instruction{0x54, 1, "BIT 2,H", func(cpu *CPU) { cpu.Bit(2, RegisterH) }},
-	0x55: &instruction{0x55, 1, "BIT 2,L", func(cpu *CPU) { cpu.Bit(2, RegisterL) }},
+	0x57: &instruction{0x57, 2, "BIT 2,A", func(cpu *CPU) { cpu.BitA(2) }},
+	0x50: &instruction{0x50, 2, "BIT 2,B", func(cpu *CPU) { cpu.Bit(2, RegisterB) }},
+	0x51: &instruction{0x51, 2, "BIT 2,C", func(cpu *CPU) { cpu.Bit(2, RegisterC) }},
+	0x52: &instruction{0x52, 2, "BIT 2,D", func(cpu *CPU) { cpu.Bit(2, RegisterD) }},
+	0x53: &instruction{0x53, 2, "BIT 2,E", func(cpu *CPU) { cpu.Bit(2, RegisterE) }},
+	0x54: &instruction{0x54, 2, "BIT 2,H", func(cpu *CPU) { cpu.Bit(2, RegisterH) }},
+	0x55: &instruction{0x55, 2, "BIT 2,L", func(cpu *CPU) { cpu.Bit(2, RegisterL) }},
 
 	// Flag (Z) <- ^Memory[HL][2]
-	0x56: &instruction{0x56, 2, "BIT 2,(HL)", func(cpu *CPU) { cpu.BitHL(2) }},
+	0x56: &instruction{0x56, 3, "BIT 2,(HL)", func(cpu *CPU) { cpu.BitHL(2) }},
 
 	// Flag (Z) <- ^Register (A, B, C, D, E, H, L)[3]
-	0x5F: &instruction{0x5F, 1, "BIT 3,A", func(cpu *CPU) { cpu.BitA(3) }},
-	0x58: &instruction{0x58, 1, "BIT 3,B", func(cpu *CPU) { cpu.Bit(3, RegisterB) }},
-	0x59: &instruction{0x59, 1, "BIT 3,C", func(cpu *CPU) { cpu.Bit(3, RegisterC) }},
-	0x5A: &instruction{0x5A, 1, "BIT 3,D", func(cpu *CPU) { cpu.Bit(3, RegisterD) }},
-	0x5B: &instruction{0x5B, 1, "BIT 3,E", func(cpu *CPU) { cpu.Bit(3, RegisterE) }},
-	0x5C: &instruction{0x5C, 1, "BIT 3,H", func(cpu *CPU) { cpu.Bit(3, RegisterH) }},
-	0x5D: &instruction{0x5D, 1, "BIT 3,L", func(cpu *CPU) { cpu.Bit(3, RegisterL) }},
+	0x5F: &instruction{0x5F, 2, "BIT 3,A", func(cpu *CPU) { cpu.BitA(3) }},
+	0x58: &instruction{0x58, 2, "BIT 3,B", func(cpu *CPU) { cpu.Bit(3, RegisterB) }},
+	0x59: &instruction{0x59, 2, "BIT 3,C", func(cpu *CPU) { cpu.Bit(3, RegisterC) }},
+	0x5A: &instruction{0x5A, 2, "BIT 3,D", func(cpu *CPU) { cpu.Bit(3, RegisterD) }},
+	0x5B: &instruction{0x5B, 2, "BIT 3,E", func(cpu *CPU) { cpu.Bit(3, RegisterE) }},
+	0x5C: &instruction{0x5C, 2, "BIT 3,H", func(cpu *CPU) { cpu.Bit(3, RegisterH) }},
+	0x5D: &instruction{0x5D, 2, "BIT 3,L", func(cpu *CPU) { cpu.Bit(3, RegisterL) }},
 
 	// Flag (Z) <- ^Memory[HL][3]
-	0x5E: &instruction{0x5E, 2, "BIT 3,(HL)", func(cpu *CPU) { cpu.BitHL(3) }},
+	0x5E: &instruction{0x5E, 3, "BIT 3,(HL)", func(cpu *CPU) { cpu.BitHL(3) }},
 
 	// Flag (Z) <- ^Register (A, B, C, D, E, H, L)[4]
-	0x67: &instruction{0x67, 1, "BIT 4,A", func(cpu *CPU) { cpu.BitA(4) }},
-	0x60: &instruction{0x60, 1, "BIT 4,B", func(cpu *CPU) { cpu.Bit(4, RegisterB) }},
-	0x61: &instruction{0x61, 1, "BIT 4,C", func(cpu *CPU) { cpu.Bit(4, RegisterC) }},
-	0x62: &instruction{0x62, 1, "BIT 4,D", func(cpu *CPU) { cpu.Bit(4, RegisterD) }},
-	0x63: &instruction{0x63, 1, "BIT 4,E", func(cpu *CPU) { cpu.Bit(4, RegisterE) }},
-	0x64: &instruction{0x64, 1, "BIT 4,H", func(cpu *CPU) { cpu.Bit(4, RegisterH) }},
-	0x65: &instruction{0x65, 1, "BIT 4,L", func(cpu *CPU) { cpu.Bit(4, RegisterL) }},
+	0x67: &instruction{0x67, 2, "BIT 4,A", func(cpu *CPU) { cpu.BitA(4) }},
+	0x60: &instruction{0x60, 2, "BIT 4,B", func(cpu *CPU) { cpu.Bit(4, RegisterB) }},
+	0x61: &instruction{0x61, 2, "BIT 4,C", func(cpu *CPU) { cpu.Bit(4, RegisterC) }},
+	0x62: &instruction{0x62, 2, "BIT 4,D", func(cpu *CPU) { cpu.Bit(4, RegisterD) }},
+	0x63: &instruction{0x63, 2, "BIT 4,E", func(cpu *CPU) { cpu.Bit(4, RegisterE) }},
+	0x64: &instruction{0x64, 2, "BIT 4,H", func(cpu *CPU) { cpu.Bit(4, RegisterH) }},
+	0x65: &instruction{0x65, 2, "BIT 4,L", func(cpu *CPU) { cpu.Bit(4, RegisterL) }},
 
 	// Flag (Z) <- ^Memory[HL][4]
-	0x66: &instruction{0x66, 2, "BIT 4,(HL)", func(cpu *CPU) { cpu.BitHL(4) }},
+	0x66: &instruction{0x66, 3, "BIT 4,(HL)", func(cpu *CPU) { cpu.BitHL(4) }},
 
 	// Flag (Z) <- ^Register (A, B, C, D, E, H, L)[5]
-	0x6F: &instruction{0x6F, 1, "BIT 5,A", func(cpu *CPU) { cpu.BitA(5) }},
-	0x68: &instruction{0x68, 1, "BIT 5,B", func(cpu *CPU) { cpu.Bit(5, RegisterB) }},
-	0x69: &instruction{0x69, 1, "BIT 5,C", func(cpu *CPU) { cpu.Bit(5, RegisterC) }},
-	0x6A: &instruction{0x6A, 1, "BIT 5,D", func(cpu *CPU) { cpu.Bit(5, RegisterD) }},
-	0x6B: &instruction{0x6B, 1, "BIT 5,E", func(cpu *CPU) { cpu.Bit(5, RegisterE) }},
-	0x6C: &instruction{0x6C, 1, "BIT 5,H", func(cpu *CPU) { cpu.Bit(5, RegisterH) }},
-	0x6D: &instruction{0x6D, 1, "BIT 5,L", func(cpu *CPU) { cpu.Bit(5, RegisterL) }},
+	0x6F: &instruction{0x6F, 2, "BIT 5,A", func(cpu *CPU) { cpu.BitA(5) }},
+	0x68: &instruction{0x68, 2, "BIT 5,B", func(cpu *CPU) { cpu.Bit(5, RegisterB) }},
+	0x69: &instruction{0x69, 2, "BIT 5,C", func(cpu *CPU) { cpu.Bit(5, RegisterC) }},
+	0x6A: &instruction{0x6A, 2, "BIT 5,D", func(cpu *CPU) { cpu.Bit(5, RegisterD) }},
+	0x6B: &instruction{0x6B, 2, "BIT 5,E", func(cpu *CPU) { cpu.Bit(5, RegisterE) }},
+	0x6C: &instruction{0x6C, 2, "BIT 5,H", func(cpu *CPU) { cpu.Bit(5, RegisterH) }},
+	0x6D: &instruction{0x6D, 2, "BIT 5,L", func(cpu *CPU) { cpu.Bit(5, RegisterL) }},
 
 	// Flag (Z) <- ^Memory[HL][5]
-	0x6E: &instruction{0x6E, 2, "BIT 5,(HL)", func(cpu *CPU) { cpu.BitHL(5) }},
+	0x6E: &instruction{0x6E, 3, "BIT 5,(HL)", func(cpu *CPU) { cpu.BitHL(5) }},
 
 	// Flag (Z) <- ^Register (A, B, C, D, E, H, L)[6]
-	0x77: &instruction{0x77, 1, "BIT 6,A", func(cpu *CPU) { cpu.BitA(6) }},
-	0x70: &instruction{0x70, 1, "BIT 6,B", func(cpu *CPU) { cpu.Bit(6, RegisterB) }},
-	0x71: &instruction{0x71, 1, "BIT 6,C", func(cpu *CPU) { cpu.Bit(6, RegisterC) }},
-	0x72: &instruction{0x72, 1, "BIT 6,D", func(cpu *CPU) { cpu.Bit(6, RegisterD) }},
-	0x73: &instruction{0x73, 1, "BIT 6,E", func(cpu *CPU) { cpu.Bit(6, RegisterE) }},
-	0x74: &instruction{0x74, 1, "BIT 6,H", func(cpu *CPU) { cpu.Bit(6, RegisterH) }},
-	0x75: &instruction{0x75, 1, "BIT 6,L", func(cpu *CPU) { cpu.Bit(6, RegisterL) }},
+	0x77: &instruction{0x77, 2, "BIT 6,A", func(cpu *CPU) { cpu.BitA(6) }},
+	0x70: &instruction{0x70, 2, "BIT 6,B", func(cpu *CPU) { cpu.Bit(6, RegisterB) }},
+	0x71: &instruction{0x71, 2, "BIT 6,C", func(cpu *CPU) { cpu.Bit(6, RegisterC) }},
+	0x72: &instruction{0x72, 2, "BIT 6,D", func(cpu *CPU) { cpu.Bit(6, RegisterD) }},
+	0x73: &instruction{0x73, 2, "BIT 6,E", func(cpu *CPU) { cpu.Bit(6, RegisterE) }},
+	0x74: &instruction{0x74, 2, "BIT 6,H", func(cpu *CPU) { cpu.Bit(6, RegisterH) }},
+	0x75: &instruction{0x75, 2, "BIT 6,L", func(cpu *CPU) { cpu.Bit(6, RegisterL) }},
 
 	// Flag (Z) <- ^Memory[HL][6]
-	0x76: &instruction{0x76, 2, "BIT 6,(HL)", func(cpu *CPU) { cpu.BitHL(6) }},
+	0x76: &instruction{0x76, 3, "BIT 6,(HL)", func(cpu *CPU) { cpu.BitHL(6) }},
 
 	// Flag (Z) <- ^Register (A, B, C, D, E, H, L)[7]
-	0x7F: &instruction{0x7F, 1, "BIT 7,A", func(cpu *CPU) { cpu.BitA(7) }},
-	0x78: &instruction{0x78, 1, "BIT 7,B", func(cpu *CPU) { cpu.Bit(7, RegisterB) }},
-	0x79: &instruction{0x79, 1, "BIT 7,C", func(cpu *CPU) { cpu.Bit(7, RegisterC) }},
-	0x7A: &instruction{0x7A, 1, "BIT 7,D", func(cpu *CPU) { cpu.Bit(7, RegisterD) }},
-	0x7B: &instruction{0x7B, 1, "BIT 7,E", func(cpu *CPU) { cpu.Bit(7, RegisterE) }},
-	0x7C: &instruction{0x7C, 1, "BIT 7,H", func(cpu *CPU) { cpu.Bit(7, RegisterH) }},
-	0x7D: &instruction{0x7D, 1, "BIT 7,L", func(cpu *CPU) { cpu.Bit(7, RegisterL) }},
+	0x7F: &instruction{0x7F, 2, "BIT 7,A", func(cpu *CPU) { cpu.BitA(7) }},
+	0x78: &instruction{0x78, 2, "BIT 7,B", func(cpu *CPU) { cpu.Bit(7, RegisterB) }},
+	0x79: &instruction{0x79, 2, "BIT 7,C", func(cpu *CPU) { cpu.Bit(7, RegisterC) }},
+	0x7A: &instruction{0x7A, 2, "BIT 7,D", func(cpu *CPU) { cpu.Bit(7, RegisterD) }},
+	0x7B: &instruction{0x7B, 2, "BIT 7,E", func(cpu *CPU) { cpu.Bit(7, RegisterE) }},
+	0x7C: &instruction{0x7C, 2, "BIT 7,H", func(cpu *CPU) { cpu.Bit(7, RegisterH) }},
+	0x7D: &instruction{0x7D, 2, "BIT 7,L", func(cpu *CPU) { cpu.Bit(7, RegisterL) }},
 
 	// Flag (Z) <- ^Memory[HL][7]
-	0x7E: &instruction{0x7E, 2, "BIT 7,(HL)", func(cpu *CPU) { cpu.BitHL(7) }},
+	0x7E: &instruction{0x7E, 3, "BIT 7,(HL)", func(cpu *CPU) { cpu.BitHL(7) }},
 
 	// Register (A, B, C, D, E, H, L)[0] <- 0
-	0x87: &instruction{0x87, 1, "RES 0,A", func(cpu *CPU) { cpu.ResetA(0) }},
-	0x80: &instruction{0x80, 1, "RES 0,B", func(cpu *CPU) { cpu.Reset(0, RegisterB) }},
-	0x81: &instruction{0x81, 1, "RES 0,C", func(cpu *CPU) { cpu.Reset(0, RegisterC) }},
-	0x82: &instruction{0x82, 1, "RES 0,D", func(cpu *CPU) { cpu.Reset(0, RegisterD) }},
-	0x83: &instruction{0x83, 1, "RES 0,E", func(cpu *CPU) { cpu.Reset(0, RegisterE) }},
-	0x84: &instruction{0x84, 1, "RES 0,H", func(cpu *CPU) { cpu.Reset(0, RegisterH) }},
-	0x85: &instruction{0x85, 1, "RES 0,L", func(cpu *CPU) { cpu.Reset(0, RegisterL) }},
+	0x87: &instruction{0x87, 2, "RES 0,A", func(cpu *CPU) { cpu.ResetA(0) }},
+	0x80: &instruction{0x80, 2, "RES 0,B", func(cpu *CPU) { cpu.Reset(0, RegisterB) }},
+	0x81: &instruction{0x81, 2, "RES 0,C", func(cpu *CPU) { cpu.Reset(0, RegisterC) }},
+	0x82: &instruction{0x82, 2, "RES 0,D", func(cpu *CPU) { cpu.Reset(0, RegisterD) }},
+	0x83: &instruction{0x83, 2, "RES 0,E", func(cpu *CPU) { cpu.Reset(0, RegisterE) }},
+	0x84: &instruction{0x84, 2, "RES 0,H", func(cpu *CPU) { cpu.Reset(0, RegisterH) }},
+	0x85: &instruction{0x85, 2, "RES 0,L", func(cpu *CPU) { cpu.Reset(0, RegisterL) }},
 
 	// Memory[HL][0] <- 0
-	0x86: &instruction{0x86, 3, "RES 0,(HL)", func(cpu *CPU) { cpu.ResetHL(0) }},
+	0x86: &instruction{0x86, 4, "RES 0,(HL)", func(cpu *CPU) { cpu.ResetHL(0) }},
 
 	// Register (A, B, C, D, E, H, L)[1] <- 0
-	0x8F: &instruction{0x8F, 1, "RES 1,A", func(cpu *CPU) { cpu.ResetA(1) }},
-	0x88: &instruction{0x88, 1, "RES 1,B", func(cpu *CPU) { cpu.Reset(1, RegisterB) }},
-	0x89: &instruction{0x89, 1, "RES 1,C", func(cpu *CPU) { cpu.Reset(1, RegisterC) }},
-	0x8A: &instruction{0x8A, 1, "RES 1,D", func(cpu *CPU) { cpu.Reset(1, RegisterD) }},
-	0x8B: &instruction{0x8B, 1, "RES 1,E", func(cpu *CPU) { cpu.Reset(1, RegisterE) }},
-	0x8C: &instruction{0x8C, 1, "RES 1,H", func(cpu *CPU) { cpu.Reset(1, RegisterH) }},
-	0x8D: &instruction{0x8D, 1, "RES 1,L", func(cpu *CPU) { cpu.Reset(1, RegisterL) }},
+	0x8F: &instruction{0x8F, 2, "RES 1,A", func(cpu *CPU) { cpu.ResetA(1) }},
+	0x88: &instruction{0x88, 2, "RES 1,B", func(cpu *CPU) { cpu.Reset(1, RegisterB) }},
+	0x89: &instruction{0x89, 2, "RES 1,C", func(cpu *CPU) { cpu.Reset(1, RegisterC) }},
+	0x8A: &instruction{0x8A, 2, "RES 1,D", func(cpu *CPU) { cpu.Reset(1, RegisterD) }},
+	0x8B: &instruction{0x8B, 2, "RES 1,E", func(cpu *CPU) { cpu.Reset(1, RegisterE) }},
+	0x8C: &instruction{0x8C, 2, "RES 1,H", func(cpu *CPU) { cpu.Reset(1, RegisterH) }},
+	0x8D: &instruction{0x8D, 2, "RES 1,L", func(cpu *CPU) { cpu.Reset(1, RegisterL) }},
 
 	// Memory[HL][1] <- 0
-	0x8E: &instruction{0x8E, 3, "RES 1,(HL)", func(cpu *CPU) { cpu.ResetHL(1) }},
+	0x8E: &instruction{0x8E, 4, "RES 1,(HL)", func(cpu *CPU) { cpu.ResetHL(1) }},
 
 	// Register (A, B, C, D, E, H, L)[2] <- 0
-	0x97: &instruction{0x97, 1, "RES 2,A", func(cpu *CPU) { cpu.ResetA(2) }},
-	0x90: &instruction{0x90, 1, "RES 2,B", func(cpu *CPU) { cpu.Reset(2, RegisterB) }},
-	0x91: &instruction{0x91, 1, "RES 2,C", func(cpu *CPU) { cpu.Reset(2, RegisterC) }},
-	0x92: &instruction{0x92, 1, "RES 2,D", func(cpu *CPU) { cpu.Reset(2, RegisterD) }},
-	0x93: &instruction{0x93, 1, "RES 2,E", func(cpu *CPU) { cpu.Reset(2, RegisterE) }},
-	0x94: &instruction{0x94, 1, "RES 2,H", func(cpu *CPU) { cpu.Reset(2, RegisterH) }},
-	0x95: &instruction{0x95, 1, "RES 2,L", func(cpu *CPU) { cpu.Reset(2, RegisterL) }},
+	0x97: &instruction{0x97, 2, "RES 2,A", func(cpu *CPU) { cpu.ResetA(2) }},
+	0x90: &instruction{0x90, 2, "RES 2,B", func(cpu *CPU) { cpu.Reset(2, RegisterB) }},
+	0x91: &instruction{0x91, 2, "RES 2,C", func(cpu *CPU) { cpu.Reset(2, RegisterC) }},
+	0x92: &instruction{0x92, 2, "RES 2,D", func(cpu *CPU) { cpu.Reset(2, RegisterD) }},
+	0x93: &instruction{0x93, 2, "RES 2,E", func(cpu *CPU) { cpu.Reset(2, RegisterE) }},
+	0x94: &instruction{0x94, 2, "RES 2,H", func(cpu *CPU) { cpu.Reset(2, RegisterH) }},
+	0x95: &instruction{0x95, 2, "RES 2,L", func(cpu *CPU) { cpu.Reset(2, RegisterL) }},
 
 	// Memory[HL][2] <- 0
-	0x96: &instruction{0x96, 3, "RES 2,(HL)", func(cpu *CPU) { cpu.ResetHL(2) }},
+	0x96: &instruction{0x96, 4, "RES 2,(HL)", func(cpu *CPU) { cpu.ResetHL(2) }},
 
 	// Register (A, B, C, D, E, H, L)[3] <- 0
-	0x9F: &instruction{0x9F, 1, "RES 3,A", func(cpu *CPU) { cpu.ResetA(3) }},
-	0x98: &instruction{0x98, 1, "RES 3,B", func(cpu *CPU) { cpu.Reset(3, RegisterB) }},
-	0x99: &instruction{0x99, 1, "RES 3,C", func(cpu *CPU) { cpu.Reset(3, RegisterC) }},
-	0x9A: &instruction{0x9A, 1, "RES 3,D", func(cpu *CPU) { cpu.Reset(3, RegisterD) }},
-	0x9B: &instruction{0x9B, 1, "RES 3,E", func(cpu *CPU) { cpu.Reset(3, RegisterE) }},
-	0x9C: &instruction{0x9C, 1, "RES 3,H", func(cpu *CPU) { cpu.Reset(3, RegisterH) }},
-	0x9D: &instruction{0x9D, 1, "RES 3,L", func(cpu *CPU) { cpu.Reset(3, RegisterL) }},
+	0x9F: &instruction{0x9F, 2, "RES 3,A", func(cpu *CPU) { cpu.ResetA(3) }},
+	0x98: &instruction{0x98, 2, "RES 3,B", func(cpu *CPU) { cpu.Reset(3, RegisterB) }},
+	0x99: &instruction{0x99, 2, "RES 3,C", func(cpu *CPU) { cpu.Reset(3, RegisterC) }},
+	0x9A: &instruction{0x9A, 2, "RES 3,D", func(cpu *CPU) { cpu.Reset(3, RegisterD) }},
+	0x9B: &instruction{0x9B, 2, "RES 3,E", func(cpu *CPU) { cpu.Reset(3, RegisterE) }},
+	0x9C: &instruction{0x9C, 2, "RES 3,H", func(cpu *CPU) { cpu.Reset(3, RegisterH) }},
+	0x9D: &instruction{0x9D, 2, "RES 3,L", func(cpu *CPU) { cpu.Reset(3, RegisterL) }},
 
 	// Memory[HL][3] <- 0
-	0x9E: &instruction{0x9E, 3, "RES 3,(HL)", func(cpu *CPU) { cpu.ResetHL(3) }},
+	0x9E: &instruction{0x9E, 4, "RES 3,(HL)", func(cpu *CPU) { cpu.ResetHL(3) }},
 
 	// Register (A, B, C, D, E, H, L)[4] <- 0
-	0xA7: &instruction{0xA7, 1, "RES 4,A", func(cpu *CPU) { cpu.ResetA(4) }},
-	0xA0: &instruction{0xA0, 1, "RES 4,B", func(cpu *CPU) { cpu.Reset(4, RegisterB) }},
-	0xA1: &instruction{0xA1, 1, "RES 4,C", func(cpu *CPU) { cpu.Reset(4, RegisterC) }},
-	0xA2: &instruction{0xA2, 1, "RES 4,D", func(cpu *CPU) { cpu.Reset(4, RegisterD) }},
-	0xA3: &instruction{0xA3, 1, "RES 4,E", func(cpu *CPU) { cpu.Reset(4, RegisterE) }},
-	0xA4: &instruction{0xA4, 1, "RES 4,H", func(cpu *CPU) { cpu.Reset(4, RegisterH) }},
-	0xA5: &instruction{0xA5, 1, "RES 4,L", func(cpu *CPU) { cpu.Reset(4, RegisterL) }},
+	0xA7: &instruction{0xA7, 2, "RES 4,A", func(cpu *CPU) { cpu.ResetA(4) }},
+	0xA0: &instruction{0xA0, 2, "RES 4,B", func(cpu *CPU) { cpu.Reset(4, RegisterB) }},
+	0xA1: &instruction{0xA1, 2, "RES 4,C", func(cpu *CPU) { cpu.Reset(4, RegisterC) }},
+	0xA2: &instruction{0xA2, 2, "RES 4,D", func(cpu *CPU) { cpu.Reset(4, RegisterD) }},
+	0xA3: &instruction{0xA3, 2, "RES 4,E", func(cpu *CPU) { cpu.Reset(4, RegisterE) }},
+	0xA4: &instruction{0xA4, 2, "RES 4,H", func(cpu *CPU) { cpu.Reset(4, RegisterH) }},
+	0xA5: &instruction{0xA5, 2, "RES 4,L", func(cpu *CPU) { cpu.Reset(4, RegisterL) }},
 
 	// Memory[HL][4] <- 0
-	0xA6: &instruction{0xA6, 3, "RES 4,(HL)", func(cpu *CPU) { cpu.ResetHL(4) }},
+	0xA6: &instruction{0xA6, 4, "RES 4,(HL)", func(cpu *CPU) { cpu.ResetHL(4) }},
 
 	// Register (A, B, C, D, E, H, L)[5] <- 0
-	0xAF: &instruction{0xAF, 1, "RES 5,A", func(cpu *CPU) { cpu.ResetA(5) }},
-	0xA8: &instruction{0xA8, 1, "RES 5,B", func(cpu *CPU) { cpu.Reset(5, RegisterB) }},
-	0xA9: &instruction{0xA9, 1, "RES 5,C", func(cpu *CPU) { cpu.Reset(5, RegisterC) }},
-	0xAA: &instruction{0xAA, 1, "RES 5,D", func(cpu *CPU) { cpu.Reset(5, RegisterD) }},
-	0xAB: &instruction{0xAB, 1, "RES 5,E", func(cpu *CPU) { cpu.Reset(5, RegisterE) }},
-	0xAC: &instruction{0xAC, 1, "RES 5,H", func(cpu *CPU) { cpu.Reset(5, RegisterH) }},
-	0xAD: &instruction{0xAD, 1, "RES 5,L", func(cpu *CPU) { cpu.Reset(5, RegisterL) }},
+	0xAF: &instruction{0xAF, 2, "RES 5,A", func(cpu *CPU) { cpu.ResetA(5) }},
+	0xA8: &instruction{0xA8, 2, "RES 5,B", func(cpu *CPU) { cpu.Reset(5, RegisterB) }},
+	0xA9: &instruction{0xA9, 2, "RES 5,C", func(cpu *CPU) { cpu.Reset(5, RegisterC) }},
+	0xAA: &instruction{0xAA, 2, "RES 5,D", func(cpu *CPU) { cpu.Reset(5, RegisterD) }},
+	0xAB: &instruction{0xAB, 2, "RES 5,E", func(cpu *CPU) { cpu.Reset(5, RegisterE) }},
+	0xAC: &instruction{0xAC, 2, "RES 5,H", func(cpu *CPU) { cpu.Reset(5, RegisterH) }},
+	0xAD: &instruction{0xAD, 2, "RES 5,L", func(cpu *CPU) { cpu.Reset(5, RegisterL) }},
 
 	// Memory[HL][5] <- 0
-	0xAE: &instruction{0xAE, 3, "RES 5,(HL)", func(cpu *CPU) { cpu.ResetHL(5) }},
+	0xAE: &instruction{0xAE, 4, "RES 5,(HL)", func(cpu *CPU) { cpu.ResetHL(5) }},
 
 	// Register (A, B, C, D, E, H, L)[6] <- 0
-	0xB7: &instruction{0xB7, 1, "RES 6,A", func(cpu *CPU) { cpu.ResetA(6) }},
-	0xB0: &instruction{0xB0, 1, "RES 6,B", func(cpu *CPU) { cpu.Reset(6, RegisterB) }},
-	0xB1: &instruction{0xB1, 1, "RES 6,C", func(cpu *CPU) { cpu.Reset(6, RegisterC) }},
-	0xB2: &instruction{0xB2, 1, "RES 6,D", func(cpu *CPU) { cpu.Reset(6, RegisterD) }},
-	0xB3: &instruction{0xB3, 1, "RES 6,E", func(cpu *CPU) { cpu.Reset(6, RegisterE) }},
-	0xB4: &instruction{0xB4, 1, "RES 6,H", func(cpu *CPU) { cpu.Reset(6, RegisterH) }},
-	0xB5: &instruction{0xB5, 1, "RES 6,L", func(cpu *CPU) { cpu.Reset(6, RegisterL) }},
+	0xB7: &instruction{0xB7, 2, "RES 6,A", func(cpu *CPU) { cpu.ResetA(6) }},
+	0xB0: &instruction{0xB0, 2, "RES 6,B", func(cpu *CPU) { cpu.Reset(6, RegisterB) }},
+	0xB1: &instruction{0xB1, 2, "RES 6,C", func(cpu *CPU) { cpu.Reset(6, RegisterC) }},
+	0xB2: &instruction{0xB2, 2, "RES 6,D", func(cpu *CPU) { cpu.Reset(6, RegisterD) }},
+	0xB3: &instruction{0xB3, 2, "RES 6,E", func(cpu *CPU) { cpu.Reset(6, RegisterE) }},
+	0xB4: &instruction{0xB4, 2, "RES 6,H", func(cpu *CPU) { cpu.Reset(6, RegisterH) }},
+	0xB5: &instruction{0xB5, 2, "RES 6,L", func(cpu *CPU) { cpu.Reset(6, RegisterL) }},
 
 	// Memory[HL][6] <- 0
-	0xB6: &instruction{0xB6, 3, "RES 6,(HL)", func(cpu *CPU) { cpu.ResetHL(6) }},
+	0xB6: &instruction{0xB6, 4, "RES 6,(HL)", func(cpu *CPU) { cpu.ResetHL(6) }},
 
 	// Register (A, B, C, D, E, H, L)[7] <- 0
-	0xBF: &instruction{0xBF, 1, "RES 7,A", func(cpu *CPU) { cpu.ResetA(7) }},
-	0xB8: &instruction{0xB8, 1, "RES 7,B", func(cpu *CPU) { cpu.Reset(7, RegisterB) }},
-	0xB9: &instruction{0xB9, 1, "RES 7,C", func(cpu *CPU) { cpu.Reset(7, RegisterC) }},
-	0xBA: &instruction{0xBA, 1, "RES 7,D", func(cpu *CPU) { cpu.Reset(7, RegisterD) }},
-	0xBB: &instruction{0xBB, 1, "RES 7,E", func(cpu *CPU) { cpu.Reset(7, RegisterE) }},
-	0xBC: &instruction{0xBC, 1, "RES 7,H", func(cpu *CPU) { cpu.Reset(7, RegisterH) }},
-	0xBD: &instruction{0xBD, 1, "RES 7,L", func(cpu *CPU) { cpu.Reset(7, RegisterL) }},
+	0xBF: &instruction{0xBF, 2, "RES 7,A", func(cpu *CPU) { cpu.ResetA(7) }},
+	0xB8: &instruction{0xB8, 2, "RES 7,B", func(cpu *CPU) { cpu.Reset(7, RegisterB) }},
+	0xB9: &instruction{0xB9, 2, "RES 7,C", func(cpu *CPU) { cpu.Reset(7, RegisterC) }},
+	0xBA: &instruction{0xBA, 2, "RES 7,D", func(cpu *CPU) { cpu.Reset(7, RegisterD) }},
+	0xBB: &instruction{0xBB, 2, "RES 7,E", func(cpu *CPU) { cpu.Reset(7, RegisterE) }},
+	0xBC: &instruction{0xBC, 2, "RES 7,H", func(cpu *CPU) { cpu.Reset(7, RegisterH) }},
+	0xBD: &instruction{0xBD, 2, "RES 7,L", func(cpu *CPU) { cpu.Reset(7, RegisterL) }},
 
 	// Memory[HL][7] <- 0
-	0xBE: &instruction{0xBE, 3, "RES 7,(HL)", func(cpu *CPU) { cpu.ResetHL(7) }},
+	0xBE: &instruction{0xBE, 4, "RES 7,(HL)", func(cpu *CPU) { cpu.ResetHL(7) }},
 
 	// Register (A, B, C, D, E, H, L)[0] <- 1
-	0xC7: &instruction{0xC7, 1, "SET 0,A", func(cpu *CPU) { cpu.SetA(0) }},
-	0xC0: &instruction{0xC0, 1, "SET 0,B", func(cpu *CPU) { cpu.Set(0, RegisterB) }},
-	0xC1: &instruction{0xC1, 1, "SET 0,C", func(cpu *CPU) { cpu.Set(0, RegisterC) }},
-	0xC2: &instruction{0xC2, 1, "SET 0,D", func(cpu *CPU) { cpu.Set(0, RegisterD) }},
-	0xC3: &instruction{0xC3, 1, "SET 0,E", func(cpu *CPU) { cpu.Set(0, RegisterE) }},
-	0xC4: &instruction{0xC4, 1, "SET 0,H", func(cpu *CPU) { cpu.Set(0, RegisterH) }},
-	0xC5: &instruction{0xC5, 1, "SET 0,L", func(cpu *CPU) { cpu.Set(0, RegisterL) }},
+	0xC7: &instruction{0xC7, 2, "SET 0,A", func(cpu *CPU) { cpu.SetA(0) }},
+	0xC0: &instruction{0xC0, 2, "SET 0,B", func(cpu *CPU) { cpu.Set(0, RegisterB) }},
+	0xC1: &instruction{0xC1, 2, "SET 0,C", func(cpu *CPU) { cpu.Set(0, RegisterC) }},
+	0xC2: &instruction{0xC2, 2, "SET 0,D", func(cpu *CPU) { cpu.Set(0, RegisterD) }},
+	0xC3: &instruction{0xC3, 2, "SET 0,E", func(cpu *CPU) { cpu.Set(0, RegisterE) }},
+	0xC4: &instruction{0xC4, 2, "SET 0,H", func(cpu *CPU) { cpu.Set(0, RegisterH) }},
+	0xC5: &instruction{0xC5, 2, "SET 0,L", func(cpu *CPU) { cpu.Set(0, RegisterL) }},
 
 	// Memory[HL][0] <- 1
-	0xC6: &instruction{0xC6, 3, "SET 0,(HL)", func(cpu *CPU) { cpu.SetHL(0) }},
+	0xC6: &instruction{0xC6, 4, "SET 0,(HL)", func(cpu *CPU) { cpu.SetHL(0) }},
 
 	// Register (A, B, C, D, E, H, L)[1] <- 1
-	0xCF: &instruction{0xCF, 1, "SET 1,A", func(cpu *CPU) { cpu.SetA(1) }},
-	0xC8: &instruction{0xC8, 1, "SET 1,B", func(cpu *CPU) { cpu.Set(1, RegisterB) }},
-	0xC9: &instruction{0xC9, 1, "SET 1,C", func(cpu *CPU) { cpu.Set(1, RegisterC) }},
-	0xCA: &instruction{0xCA, 1, "SET 1,D", func(cpu *CPU) { cpu.Set(1, RegisterD) }},
-	0xCB: &instruction{0xCB, 1, "SET 1,E", func(cpu *CPU) { cpu.Set(1, RegisterE) }},
-	0xCC: &instruction{0xCC, 1, "SET 1,H", func(cpu *CPU) { cpu.Set(1, RegisterH) }},
-	0xCD: &instruction{0xCD, 1, "SET 1,L", func(cpu *CPU) { cpu.Set(1, RegisterL) }},
+	0xCF: &instruction{0xCF, 2, "SET 1,A", func(cpu *CPU) { cpu.SetA(1) }},
+	0xC8: &instruction{0xC8, 2, "SET 1,B", func(cpu *CPU) { cpu.Set(1, RegisterB) }},
+	0xC9: &instruction{0xC9, 2, "SET 1,C", func(cpu *CPU) { cpu.Set(1, RegisterC) }},
+	0xCA: &instruction{0xCA, 2, "SET 1,D", func(cpu *CPU) { cpu.Set(1, RegisterD) }},
+	0xCB: &instruction{0xCB, 2, "SET 1,E", func(cpu *CPU) { cpu.Set(1, RegisterE) }},
+	0xCC: &instruction{0xCC, 2, "SET 1,H", func(cpu *CPU) { cpu.Set(1, RegisterH) }},
+	0xCD: &instruction{0xCD, 2, "SET 1,L", func(cpu *CPU) { cpu.Set(1, RegisterL) }},
 
 	// Memory[HL][1] <- 1
-	0xCE: &instruction{0xCE, 3, "SET 1,(HL)", func(cpu *CPU) { cpu.SetHL(1) }},
+	0xCE: &instruction{0xCE, 4, "SET 1,(HL)", func(cpu *CPU) { cpu.SetHL(1) }},
 
 	// Register (A, B, C, D, E, H, L)[2] <- 1
-	0xD7: &instruction{0xD7, 1, "SET 2,A", func(cpu *CPU) { cpu.SetA(2) }},
-	0xD0: &instruction{0xD0, 1, "SET 2,B", func(cpu *CPU) { cpu.Set(2, RegisterB) }},
-	0xD1: &instruction{0xD1, 1, "SET 2,C", func(cpu *CPU) { cpu.Set(2, RegisterC) }},
-	0xD2: &instruction{0xD2, 1, "SET 2,D", func(cpu *CPU) { cpu.Set(2, RegisterD) }},
-	0xD3: &instruction{0xD3, 1, "SET 2,E", func(cpu *CPU) { cpu.Set(2, RegisterE) }},
-	0xD4: &instruction{0xD4, 1, "SET 2,H", func(cpu *CPU) { cpu.Set(2, RegisterH) }},
-	0xD5: &instruction{0xD5, 1, "SET 2,L", func(cpu *CPU) { cpu.Set(2, RegisterL) }},
+	0xD7: &instruction{0xD7, 2, "SET 2,A", func(cpu *CPU) { cpu.SetA(2) }},
+	0xD0: &instruction{0xD0, 2, "SET 2,B", func(cpu *CPU) { cpu.Set(2, RegisterB) }},
+	0xD1: &instruction{0xD1, 2, "SET 2,C", func(cpu *CPU) { cpu.Set(2, RegisterC) }},
+	0xD2: &instruction{0xD2, 2, "SET 2,D", func(cpu *CPU) { cpu.Set(2, RegisterD) }},
+	0xD3: &instruction{0xD3, 2, "SET 2,E", func(cpu *CPU) { cpu.Set(2, RegisterE) }},
+	0xD4: &instruction{0xD4, 2, "SET 2,H", func(cpu *CPU) { cpu.Set(2, RegisterH) }},
+	0xD5: &instruction{0xD5, 2, "SET 2,L", func(cpu *CPU) { cpu.Set(2, RegisterL) }},
 
 	// Memory[HL][2] <- 1
-	0xD6: &instruction{0xD6, 3, "SET 2,(HL)", func(cpu *CPU) { cpu.SetHL(2) }},
+	0xD6: &instruction{0xD6, 4, "SET 2,(HL)", func(cpu *CPU) { cpu.SetHL(2) }},
 
 	// Register (A, B, C, D, E, H, L)[3] <- 1
-	0xDF: &instruction{0xDF, 1, "SET 3,A", func(cpu *CPU) { cpu.SetA(3) }},
-	0xD8: &instruction{0xD8, 1, "SET 3,B", func(cpu *CPU) { cpu.Set(3, RegisterB) }},
-	0xD9: &instruction{0xD9, 1, "SET 3,C", func(cpu *CPU) { cpu.Set(3, RegisterC) }},
-	0xDA: &instruction{0xDA, 1, "SET 3,D", func(cpu *CPU) { cpu.Set(3, RegisterD) }},
-	0xDB: &instruction{0xDB, 1, "SET 3,E", func(cpu *CPU) { cpu.Set(3, RegisterE) }},
-	0xDC: &instruction{0xDC, 1, "SET 3,H", func(cpu *CPU) { cpu.Set(3, RegisterH) }},
-	0xDD: &instruction{0xDD, 1, "SET 3,L", func(cpu *CPU) { cpu.Set(3, RegisterL) }},
+	0xDF: &instruction{0xDF, 2, "SET 3,A", func(cpu *CPU) { cpu.SetA(3) }},
+	0xD8: &instruction{0xD8, 2, "SET 3,B", func(cpu *CPU) { cpu.Set(3, RegisterB) }},
+	0xD9: &instruction{0xD9, 2, "SET 3,C", func(cpu *CPU) { cpu.Set(3, RegisterC) }},
+	0xDA: &instruction{0xDA, 2, "SET 3,D", func(cpu *CPU) { cpu.Set(3, RegisterD) }},
+	0xDB: &instruction{0xDB, 2, "SET 3,E", func(cpu *CPU) { cpu.Set(3, RegisterE) }},
+	0xDC: &instruction{0xDC, 2, "SET 3,H", func(cpu *CPU) { cpu.Set(3, RegisterH) }},
+	0xDD: &instruction{0xDD, 2, "SET 3,L", func(cpu *CPU) { cpu.Set(3, RegisterL) }},
 
 	// Memory[HL][3] <- 1
-	0xDE: &instruction{0xDE, 3, "SET 3,(HL)", func(cpu *CPU) { cpu.SetHL(3) }},
+	0xDE: &instruction{0xDE, 4, "SET 3,(HL)", func(cpu *CPU) { cpu.SetHL(3) }},
 
 	// Register (A, B, C, D, E, H, L)[4] <- 1
-	0xE7: &instruction{0xE7, 1, "SET 4,A", func(cpu *CPU) { cpu.SetA(4) }},
-	0xE0: &instruction{0xE0, 1, "SET 4,B", func(cpu *CPU) { cpu.Set(4, RegisterB) }},
-	0xE1: &instruction{0xE1, 1, "SET 4,C", func(cpu *CPU) { cpu.Set(4, RegisterC) }},
-	0xE2: &instruction{0xE2, 1, "SET 4,D", func(cpu *CPU) { cpu.Set(4, RegisterD) }},
-	0xE3: &instruction{0xE3, 1, "SET 4,E", func(cpu *CPU) { cpu.Set(4, RegisterE) }},
-	0xE4: &instruction{0xE4, 1, "SET 4,H", func(cpu *CPU) { cpu.Set(4, RegisterH) }},
-	0xE5: &instruction{0xE5, 1, "SET 4,L", func(cpu *CPU) { cpu.Set(4, RegisterL) }},
+	0xE7: &instruction{0xE7, 2, "SET 4,A", func(cpu *CPU) { cpu.SetA(4) }},
+	0xE0: &instruction{0xE0, 2, "SET 4,B", func(cpu *CPU) { cpu.Set(4, RegisterB) }},
+	0xE1: &instruction{0xE1, 2, "SET 4,C", func(cpu *CPU) { cpu.Set(4, RegisterC) }},
+	0xE2: &instruction{0xE2, 2, "SET 4,D", func(cpu *CPU) { cpu.Set(4, RegisterD) }},
+	0xE3: &instruction{0xE3, 2, "SET 4,E", func(cpu *CPU) { cpu.Set(4, RegisterE) }},
+	0xE4: &instruction{0xE4, 2, "SET 4,H", func(cpu *CPU) { cpu.Set(4, RegisterH) }},
+	0xE5: &instruction{0xE5, 2, "SET 4,L", func(cpu *CPU) { cpu.Set(4, RegisterL) }},
 
 	// Memory[HL][4] <- 1
-	0xE6: &instruction{0xE6, 3, "SET 4,(HL)", func(cpu *CPU) { cpu.SetHL(4) }},
+	0xE6: &instruction{0xE6, 4, "SET 4,(HL)", func(cpu *CPU) { cpu.SetHL(4) }},
 
 	// Register (A, B, C, D, E, H, L)[5] <- 1
-	0xEF: &instruction{0xEF, 1, "SET 5,A", func(cpu *CPU) { cpu.SetA(5) }},
-	0xE8: &instruction{0xE8, 1, "SET 5,B", func(cpu *CPU) { cpu.Set(5, RegisterB) }},
-	0xE9: &instruction{0xE9, 1, "SET 5,C", func(cpu *CPU) { cpu.Set(5, RegisterC) }},
-	0xEA: &instruction{0xEA, 1, "SET 5,D", func(cpu *CPU) { cpu.Set(5, RegisterD) }},
-	0xEB: &instruction{0xEB, 1, "SET 5,E", func(cpu *CPU) { cpu.Set(5, RegisterE) }},
-	0xEC: &instruction{0xEC, 1, "SET 5,H", func(cpu *CPU) { cpu.Set(5, RegisterH) }},
-	0xED: &instruction{0xED, 1, "SET 5,L", func(cpu *CPU) { cpu.Set(5, RegisterL) }},
+	0xEF: &instruction{0xEF, 2, "SET 5,A", func(cpu *CPU) { cpu.SetA(5) }},
+	0xE8: &instruction{0xE8, 2, "SET 5,B", func(cpu *CPU) { cpu.Set(5, RegisterB) }},
+	0xE9: &instruction{0xE9, 2, "SET 5,C", func(cpu *CPU) { cpu.Set(5, RegisterC) }},
+	0xEA: &instruction{0xEA, 2, "SET 5,D", func(cpu *CPU) { cpu.Set(5, RegisterD) }},
+	0xEB: &instruction{0xEB, 2, "SET 5,E", func(cpu *CPU) { cpu.Set(5, RegisterE) }},
+	0xEC: &instruction{0xEC, 2, "SET 5,H", func(cpu *CPU) { cpu.Set(5, RegisterH) }},
+	0xED: &instruction{0xED, 2, "SET 5,L", func(cpu *CPU) { cpu.Set(5, RegisterL) }},
 
 	// Memory[HL][5] <- 1
-	0xEE: &instruction{0xEE, 3, "SET 5,(HL)", func(cpu *CPU) { cpu.SetHL(5) }},
+	0xEE: &instruction{0xEE, 4, "SET 5,(HL)", func(cpu *CPU) { cpu.SetHL(5) }},
 
 	// Register (A, B, C, D, E, H, L)[6] <- 1
-	0xF7: &instruction{0xF7, 1, "SET 6,A", func(cpu *CPU) { cpu.SetA(6) }},
-	0xF0: &instruction{0xF0, 1, "SET 6,B", func(cpu *CPU) { cpu.Set(6, RegisterB) }},
-	0xF1: &instruction{0xF1, 1, "SET 6,C", func(cpu *CPU) { cpu.Set(6, RegisterC) }},
-	0xF2: &instruction{0xF2, 1, "SET 6,D", func(cpu *CPU) { cpu.Set(6, RegisterD) }},
-	0xF3: &instruction{0xF3, 1, "SET 6,E", func(cpu *CPU) { cpu.Set(6, RegisterE) }},
-	0xF4: &instruction{0xF4, 1, "SET 6,H", func(cpu *CPU) { cpu.Set(6, RegisterH) }},
-	0xF5: &instruction{0xF5, 1, "SET 6,L", func(cpu *CPU) { cpu.Set(6, RegisterL) }},
+	0xF7: &instruction{0xF7, 2, "SET 6,A", func(cpu *CPU) { cpu.SetA(6) }},
+	0xF0: &instruction{0xF0, 2, "SET 6,B", func(cpu *CPU) { cpu.Set(6, RegisterB) }},
+	0xF1: &instruction{0xF1, 2, "SET 6,C", func(cpu *CPU) { cpu.Set(6, RegisterC) }},
+	0xF2: &instruction{0xF2, 2, "SET 6,D", func(cpu *CPU) { cpu.Set(6, RegisterD) }},
+	0xF3: &instruction{0xF3, 2, "SET 6,E", func(cpu *CPU) { cpu.Set(6, RegisterE) }},
+	0xF4: &instruction{0xF4, 2, "SET 6,H", func(cpu *CPU) { cpu.Set(6, RegisterH) }},
+	0xF5: &instruction{0xF5, 2, "SET 6,L", func(cpu *CPU) { cpu.Set(6, RegisterL) }},
 
 	// Memory[HL][6] <- 1
-	0xF6: &instruction{0xF6, 3, "SET 6,(HL)", func(cpu *CPU) { cpu.SetHL(6) }},
+	0xF6: &instruction{0xF6, 4, "SET 6,(HL)", func(cpu *CPU) { cpu.SetHL(6) }},
 
 	// Register (A, B, C, D, E, H, L)[7] <- 1
-	0xFF: &instruction{0xFF, 1, "SET 7,A", func(cpu *CPU) { cpu.SetA(7) }},
-	0xF8: &instruction{0xF8, 1, "SET 7,B", func(cpu *CPU) { cpu.Set(7, RegisterB) }},
-	0xF9: &instruction{0xF9, 1, "SET 7,C", func(cpu *CPU) { cpu.Set(7, RegisterC) }},
-	0xFA: &instruction{0xFA, 1, "SET 7,D", func(cpu *CPU) { cpu.Set(7, RegisterD) }},
-	0xFB: &instruction{0xFB, 1, "SET 7,E", func(cpu *CPU) { cpu.Set(7, RegisterE) }},
-	0xFC: &instruction{0xFC, 1, "SET 7,H", func(cpu *CPU) { cpu.Set(7, RegisterH) }},
-	0xFD: &instruction{0xFD, 1, "SET 7,L", func(cpu *CPU) { cpu.Set(7, RegisterL) }},
+	0xFF: &instruction{0xFF, 2, "SET 7,A", func(cpu *CPU) { cpu.SetA(7) }},
+	0xF8: &instruction{0xF8, 2, "SET 7,B", func(cpu *CPU) { cpu.Set(7, RegisterB) }},
+	0xF9: &instruction{0xF9, 2, "SET 7,C", func(cpu *CPU) { cpu.Set(7, RegisterC) }},
+	0xFA: &instruction{0xFA, 2, "SET 7,D", func(cpu *CPU) { cpu.Set(7, RegisterD) }},
+	0xFB: &instruction{0xFB, 2, "SET 7,E", func(cpu *CPU) { cpu.Set(7, RegisterE) }},
+	0xFC: &instruction{0xFC, 2, "SET 7,H", func(cpu *CPU) { cpu.Set(7, RegisterH) }},
+	0xFD: &instruction{0xFD, 2, "SET 7,L", func(cpu *CPU) { cpu.Set(7, RegisterL) }},
 
 	// Memory[HL][7] <- 1
-	0xFE: &instruction{0xFE, 3, "SET 7,(HL)", func(cpu *CPU) { cpu.SetHL(7) }},
+	0xFE: &instruction{0xFE, 4, "SET 7,(HL)", func(cpu *CPU) { cpu.SetHL(7) }},
 }
@@ -5,25 +5,52 @@ import (
 	"testing"
 )
 
+// Returns the machine cycles taken by Step to execute the provided program,
+// with the flags set to the provided value.
+func stepCycles(flags uint8, program ...uint8) (int, *CPU) {
+	cpu := newProgram(program...)
+	cpu.r.SetAF(uint16(flags))
+
+	cycles, _ := cpu.Step()
+
+	return cycles, cpu
+}
+
 func TestMachineCycles(t *testing.T) {
 	for _, instruction := range instructions {
+		// The prefix is covered along with the CB-prefixed instructions.
+		if instruction.opcode == 0xCB {
+			continue
+		}
+
 		t.Run(fmt.Sprintf("opcode=0x%02X mnemonic=%s m=%d", instruction.opcode, instruction.mnemonic, instruction.machineCycles), func(t *testing.T) {
-			cpu := New()
-			instruction.execute(cpu)
+			// Conditional instructions are listed with the machine cycles they
+			// take when the condition holds, which it does with either all
+			// flags reset or all flags set.
+			reset, _ := stepCycles(0x00, instruction.opcode, 0x00, 0x00)
+			set, _ := stepCycles(0xF0, instruction.opcode, 0x00, 0x00)
 
-			if cpu.c.M() != uint64(instruction.machineCycles) {
-				t.Errorf("got %d, expected %d", cpu.c.M(), instruction.machineCycles)
+			m := reset
+			if set > m {
+				m = set
+			}
+
+			if m != int(instruction.machineCycles) {
+				t.Errorf("got %d, expected %d", m, instruction.machineCycles)
 			}
 		})
 	}
 
 	for _, instruction := range instructionsCB {
-		t.Run(fmt.Sprintf("opcode=0x%02X mnemonic=%s m=%d", instruction.opcode, instruction.mnemonic, instruction.machineCycles), func(t *testing.T) {
-			cpu := New()
-			instruction.execute(cpu)
+		t.Run(fmt.Sprintf("opcode=0xCB%02X mnemonic=%s m=%d", instruction.opcode, instruction.mnemonic, instruction.machineCycles), func(t *testing.T) {
+			m, cpu := stepCycles(0x00, 0xCB, instruction.opcode)
+
+			if m != int(instruction.machineCycles) {
+				t.Errorf("got %d, expected %d", m, instruction.machineCycles)
+			}
 
-			if cpu.c.M() != uint64(instruction.machineCycles) {
-				t.Errorf("got %d, expected %d", cpu.c.T(), instruction.machineCycles)
+			if pc := *cpu.r.ProgramCounter(); pc != 0xC002 {
+				t.Errorf("PC: got 0x%04X, expected 0xC002", pc)
 			}
 		})
 	}
@@ -0,0 +1,75 @@
+package cpu
+
+// Number of instructions EI waits for before enabling interrupts: EI itself,
+// and the one after it.
+const imeDelay = 2
+
+// InterruptsEnabled returns the interrupt master enable flag (IME).
+func (cpu *CPU) InterruptsEnabled() bool {
+	return cpu.ime
+}
+
+// DisableInterrupts resets the interrupt master enable flag, cancelling a
+// pending EI.
+func (cpu *CPU) DisableInterrupts() {
+	cpu.ime = false
+	cpu.imeDelay = 0
+}
+
+// EnableInterrupts sets the interrupt master enable flag once the instruction
+// following EI has been executed.
+func (cpu *CPU) EnableInterrupts() {
+	if !cpu.ime && cpu.imeDelay == 0 {
+		cpu.imeDelay = imeDelay
+	}
+}
+
+// Counts down the instructions left until a pending EI takes effect. Called
+// after every instruction.
+func (cpu *CPU) updateIME() {
+	if cpu.imeDelay == 0 {
+		return
+	}
+
+	if cpu.imeDelay--; cpu.imeDelay == 0 {
+		cpu.ime = true
+	}
+}
+
+// Services the pending interrupt with the highest priority, if interrupts are
+// enabled. The CPU waits for 2 machine cycles, pushes the program counter onto
+// the stack, and jumps to the vector of the interrupt, taking 5 machine cycles
+// in total. Returns whether an interrupt was serviced.
+func (cpu *CPU) serviceInterrupt() bool {
+	ic := cpu.mmu.Interrupts()
+	if _, ok := ic.Pending(); !cpu.ime || !ok {
+		return false
+	}
+
+	cpu.ime = false
+	cpu.tick()
+	cpu.tick()
+
+	pc := cpu.r.ProgramCounter()
+	sp := cpu.r.StackPointer()
+
+	*sp--
+	cpu.memStoreByte(*sp, uint8(*pc>>8))
+
+	// The interrupt to service is only picked once the upper byte of the
+	// program counter has been pushed. If that write cleared IE, no interrupt
+	// is left pending, and the CPU jumps to 0x0000 instead.
+	i, ok := ic.Pending()
+
+	*sp--
+	cpu.memStoreByte(*sp, uint8(*pc))
+
+	*pc = 0x0000
+	if ok {
+		ic.Acknowledge(i)
+		*pc = i.Vector()
+	}
+	cpu.tick()
+
+	return true
+}
@@ -0,0 +1,283 @@
+package cpu
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/loizoskounios/game-boy-emulator/cartridge"
+	"github.com/loizoskounios/game-boy-emulator/mmu"
+)
+
+// The mooneye-test-suite ROMs are not distributed along with the emulator.
+// Every ROM found under mooneyeDir, in any subdirectory but those in
+// mooneyeSkipDirs, is run.
+const mooneyeDir = "testdata/mooneye"
+
+// Directories of the suite holding ROMs that do not report their result
+// through the registers, and need to be checked by hand.
+var mooneyeSkipDirs = map[string]bool{"manual-only": true, "utils": true}
+
+// mooneyeTimeout is the number of machine cycles a test ROM is given to
+// report its result: 10 seconds of emulated time.
+const mooneyeTimeout = 10 * 60 * CyclesPerFrame
+
+// Matches the suffix naming the models a ROM is meant for. ROMs without one
+// are meant for every model.
+var mooneyeModels = regexp.MustCompile(`-(dmg0|dmgABC\w*|mgb|sgb2?|cgb\w*|agb|ags|[GSCA]+)\.gb$`)
+
+// Returns whether the ROM with the provided file name is meant to pass on the
+// DMG.
+func mooneyeForDMG(name string) bool {
+	m := mooneyeModels.FindStringSubmatch(name)
+	if m == nil {
+		return true
+	}
+
+	suffix := m[1]
+	if strings.HasPrefix(suffix, "dmgABC") {
+		return true
+	}
+
+	return strings.Trim(suffix, "GSCA") == "" && strings.Contains(suffix, "G")
+}
+
+// Results a mooneye test ROM reports.
+type mooneyeResult int
+
+const (
+	mooneyeRunning mooneyeResult = iota
+	mooneyePassed
+	mooneyeFailed
+	mooneyeTimedOut
+	mooneyeLockedUp
+)
+
+func (r mooneyeResult) String() string {
+	switch r {
+	case mooneyeRunning:
+		return "running"
+	case mooneyePassed:
+		return "pass"
+	case mooneyeFailed:
+		return "FAIL"
+	case mooneyeTimedOut:
+		return "TIMEOUT"
+	case mooneyeLockedUp:
+		return "LOCKED UP"
+	default:
+		return "unknown"
+	}
+}
+
+// mooneyeTracer watches for the LD B,B a test ROM executes once it is done,
+// with the Fibonacci numbers in the registers when it passed, and 0x42 in all
+// of them when it failed.
+type mooneyeTracer struct {
+	result mooneyeResult
+}
+
+func (t *mooneyeTracer) Trace(s State) {
+	if s.PCMem[0] != 0x40 {
+		return
+	}
+
+	switch [6]uint8{s.B, s.C, s.D, s.E, s.H, s.L} {
+	case [6]uint8{3, 5, 8, 13, 21, 34}:
+		t.result = mooneyePassed
+	case [6]uint8{0x42, 0x42, 0x42, 0x42, 0x42, 0x42}:
+		t.result = mooneyeFailed
+	}
+}
+
+// Runs the provided test ROM until it reports its result, locks up, or runs
+// out of time.
+func runMooneye(rom []uint8) (mooneyeResult, error) {
+	m := mmu.New()
+	if err := m.LoadROM(rom); err != nil {
+		return mooneyeRunning, err
+	}
+
+	tracer := &mooneyeTracer{}
+	cpu := New(WithMMU(m), SkipBoot(mmu.ModelDMG), WithTracer(tracer))
+	for ran := 0; ran < mooneyeTimeout; {
+		n, err := cpu.RunFor(CyclesPerFrame)
+		if err != nil {
+			return mooneyeLockedUp, err
+		}
+		if tracer.result != mooneyeRunning {
+			return tracer.result, nil
+		}
+		ran += n
+	}
+
+	return mooneyeTimedOut, nil
+}
+
+// Returns the paths of the ROMs under root that are meant to pass on the DMG,
+// leaving out those in mooneyeSkipDirs.
+func findMooneyeROMs(root string) ([]string, error) {
+	var roms []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && mooneyeSkipDirs[d.Name()] {
+			return fs.SkipDir
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".gb" && mooneyeForDMG(d.Name()) {
+			roms = append(roms, path)
+		}
+		return nil
+	})
+
+	return roms, err
+}
+
+func TestMooneye(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test ROMs in short mode")
+	}
+
+	roms, err := findMooneyeROMs(mooneyeDir)
+	if os.IsNotExist(err) {
+		t.Skipf("%s not found", mooneyeDir)
+	}
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 8, 2, ' ', 0)
+	passed := 0
+	for _, path := range roms {
+		name, _ := filepath.Rel(mooneyeDir, path)
+		t.Run(name, func(t *testing.T) {
+			rom, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+
+			result, err := runMooneye(rom)
+			fmt.Fprintf(w, "%s\t%s\n", name, result)
+			if result != mooneyePassed {
+				t.Errorf("got %s, expected %s (%v)", result, mooneyePassed, err)
+				return
+			}
+			passed++
+		})
+	}
+	w.Flush()
+
+	t.Logf("%d/%d passed\n%s", passed, len(roms), table.String())
+}
+
+func TestFindMooneyeROMs(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{
+		"acceptance/ei_timing.gb",
+		"acceptance/boot_regs-cgb.gb",
+		"emulator-only/mbc1/rom_512kb.gb",
+		"manual-only/sprite_priority.gb",
+		"utils/dump_boot_hwio.gb",
+		"README.markdown",
+	} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("got %v, expected nil", err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatalf("got %v, expected nil", err)
+		}
+	}
+
+	roms, err := findMooneyeROMs(root)
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	var got []string
+	for _, path := range roms {
+		name, _ := filepath.Rel(root, path)
+		got = append(got, filepath.ToSlash(name))
+	}
+	expected := []string{"acceptance/ei_timing.gb", "emulator-only/mbc1/rom_512kb.gb"}
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
+
+func TestMooneyeForDMG(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		expected bool
+	}{
+		{"div_timing.gb", true},
+		{"boot_regs-dmgABC.gb", true},
+		{"boot_div-dmgABCmgb.gb", true},
+		{"di_timing-GS.gb", true},
+		{"boot_hwio-G.gb", true},
+		{"boot_div-dmg0.gb", false},
+		{"boot_regs-mgb.gb", false},
+		{"boot_regs-sgb2.gb", false},
+		{"boot_hwio-S.gb", false},
+		{"boot_regs-cgb.gb", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := mooneyeForDMG(tc.name); got != tc.expected {
+				t.Errorf("got %t, expected %t", got, tc.expected)
+			}
+		})
+	}
+}
+
+// Returns a ROM image that loads the provided values into registers B, C, D,
+// E, H and L, then executes LD B,B forever.
+func newMooneyeROM(regs [6]uint8) []uint8 {
+	rom := cartridge.NewROM(0x00, 0x8000, 0x00)
+	program := []uint8{
+		0x06, regs[0], 0x0E, regs[1], 0x16, regs[2],
+		0x1E, regs[3], 0x26, regs[4], 0x2E, regs[5],
+		0x40, 0x18, 0xFD,
+	}
+	copy(rom[0x0150:], program)
+	rom[0x0100], rom[0x0101], rom[0x0102], rom[0x0103] = 0xC3, 0x50, 0x01, 0x00
+
+	cartridge.FixChecksums(rom)
+
+	return rom
+}
+
+func TestRunMooneye(t *testing.T) {
+	var testCases = []struct {
+		regs     [6]uint8
+		expected mooneyeResult
+	}{
+		{[6]uint8{3, 5, 8, 13, 21, 34}, mooneyePassed},
+		{[6]uint8{0x42, 0x42, 0x42, 0x42, 0x42, 0x42}, mooneyeFailed},
+		{[6]uint8{3, 5, 8, 13, 21, 0x42}, mooneyeTimedOut},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("regs=% X", tc.regs), func(t *testing.T) {
+			if testing.Short() && tc.expected == mooneyeTimedOut {
+				t.Skip("skipping timeout in short mode")
+			}
+
+			result, err := runMooneye(newMooneyeROM(tc.regs))
+			if err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+			if result != tc.expected {
+				t.Errorf("got %s, expected %s", result, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package cpu
+
+import "context"
+
+// CyclesPerFrame is the number of machine cycles it takes to draw a frame: 154
+// lines of 114 machine cycles each.
+const CyclesPerFrame = 17556
+
+// RunFor runs the CPU until at least the provided number of machine cycles has
+// passed, or it locks up. Returns the number of machine cycles that passed,
+// which can exceed the budget by the length of the last instruction.
+func (cpu *CPU) RunFor(cycles int) (int, error) {
+	ran := 0
+	for ran < cycles {
+		n, err := cpu.Step()
+		ran += n
+		if err != nil {
+			return ran, err
+		}
+	}
+
+	return ran, nil
+}
+
+// RunFrame runs the CPU for the length of a frame. Machine cycles run past the
+// end of a frame are taken off the next one, so that frames keep in step with
+// the system clock.
+func (cpu *CPU) RunFrame() error {
+	budget := CyclesPerFrame - cpu.overrun
+	ran, err := cpu.RunFor(budget)
+	cpu.overrun = ran - budget
+
+	return err
+}
+
+// Run runs the CPU frame after frame until the provided context is done, in
+// which case it returns nil, or the CPU locks up, in which case it returns the
+// error that caused it. Frames are run as fast as possible.
+func (cpu *CPU) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		if err := cpu.RunFrame(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package cpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestStep(t *testing.T) {
+	var testCases = []struct {
+		program []uint8
+		cycles  int
+		pc      uint16
+	}{
+		{[]uint8{0x00}, 1, 0xC001},
+		{[]uint8{0x3E, 0x12}, 2, 0xC002},
+		{[]uint8{0xCB, 0x37}, 2, 0xC002},
+		{[]uint8{0xCB, 0x46}, 3, 0xC002},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("program=% X", tc.program), func(t *testing.T) {
+			cpu := newProgram(tc.program...)
+
+			cycles, err := cpu.Step()
+			if err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+			if cycles != tc.cycles {
+				t.Errorf("cycles: got %d, expected %d", cycles, tc.cycles)
+			}
+			if pc := *cpu.r.ProgramCounter(); pc != tc.pc {
+				t.Errorf("PC: got 0x%04X, expected 0x%04X", pc, tc.pc)
+			}
+		})
+	}
+}
+
+func TestRunFor(t *testing.T) {
+	// LD A,0x3E, repeated.
+	program := make([]uint8, 16)
+	for i := range program {
+		program[i] = 0x3E
+	}
+	cpu := newProgram(program...)
+
+	ran, err := cpu.RunFor(3)
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+	if ran != 4 {
+		t.Errorf("got %d, expected 4", ran)
+	}
+
+	// HALT, with no interrupt to wake up from.
+	cpu = newProgram(0x76)
+	if err := cpu.RunFrame(); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+	if m := cpu.c.M(); m != CyclesPerFrame {
+		t.Errorf("got %d, expected %d", m, CyclesPerFrame)
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cpu := newProgram()
+	if err := cpu.Run(ctx); err != nil {
+		t.Errorf("got %v, expected nil", err)
+	}
+
+	cpu = newProgram(0x00, 0x00, 0xFC)
+	var illegal *IllegalOpcodeError
+	if err := cpu.Run(context.Background()); !errors.As(err, &illegal) {
+		t.Errorf("got %v, expected *IllegalOpcodeError", err)
+	}
+}
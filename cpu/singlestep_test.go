@@ -0,0 +1,368 @@
+package cpu
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The SingleStepTests SM83 test vectors are not distributed along with the
+// emulator. They are looked up in singleStepDir, holding one file per opcode
+// as in the v1 directory of the sm83 repository, and the ones missing are
+// skipped.
+const singleStepDir = "testdata/sm83/v1"
+
+// singleStepMaxErrors is the number of mismatching cases reported per opcode,
+// past which only the number of mismatches is.
+const singleStepMaxErrors = 10
+
+type singleStepState struct {
+	PC  uint16      `json:"pc"`
+	SP  uint16      `json:"sp"`
+	A   uint8       `json:"a"`
+	B   uint8       `json:"b"`
+	C   uint8       `json:"c"`
+	D   uint8       `json:"d"`
+	E   uint8       `json:"e"`
+	F   uint8       `json:"f"`
+	H   uint8       `json:"h"`
+	L   uint8       `json:"l"`
+	IME uint8       `json:"ime"`
+	IE  *uint8      `json:"ie"`
+	RAM [][2]uint16 `json:"ram"`
+}
+
+type singleStepCase struct {
+	Name    string            `json:"name"`
+	Initial singleStepState   `json:"initial"`
+	Final   singleStepState   `json:"final"`
+	Cycles  []json.RawMessage `json:"cycles"`
+}
+
+// Kinds of machine cycles seen on the bus.
+type busCycleKind int
+
+const (
+	busInternal busCycleKind = iota
+	busRead
+	busWrite
+)
+
+type busCycle struct {
+	kind busCycleKind
+	addr uint16
+	val  uint8
+}
+
+func (c busCycle) String() string {
+	switch c.kind {
+	case busRead:
+		return fmt.Sprintf("read 0x%02X from 0x%04X", c.val, c.addr)
+	case busWrite:
+		return fmt.Sprintf("write 0x%02X to 0x%04X", c.val, c.addr)
+	default:
+		return "internal"
+	}
+}
+
+// Decodes a cycle of a test vector, which is either null for an internal
+// cycle, or an address, a value and a string such as "r-m" or "-wm" telling
+// whether memory is read or written.
+func decodeBusCycle(raw json.RawMessage) (busCycle, error) {
+	var fields []interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		return busCycle{}, err
+	}
+	if len(fields) != 3 {
+		return busCycle{}, fmt.Errorf("malformed cycle %s", raw)
+	}
+
+	addr, _ := fields[0].(float64)
+	val, _ := fields[1].(float64)
+	kind, _ := fields[2].(string)
+
+	c := busCycle{addr: uint16(addr), val: uint8(val)}
+	switch {
+	case strings.HasPrefix(kind, "r"):
+		c.kind = busRead
+	case len(kind) > 1 && kind[1] == 'w':
+		c.kind = busWrite
+	}
+
+	return c, nil
+}
+
+// testBus is a flat 64 KiB memory recording every machine cycle, in place of
+// the MMU.
+type testBus struct {
+	mem    [0x10000]uint8
+	cycles []busCycle
+}
+
+func (b *testBus) Tick() {
+	b.cycles = append(b.cycles, busCycle{})
+}
+
+func (b *testBus) Load(addr uint16) uint8 {
+	val := b.mem[addr]
+	b.record(busCycle{busRead, addr, val})
+
+	return val
+}
+
+func (b *testBus) Store(addr uint16, val uint8) {
+	b.mem[addr] = val
+	b.record(busCycle{busWrite, addr, val})
+}
+
+// Records the access made during the machine cycle that was just ticked.
+func (b *testBus) record(c busCycle) {
+	if n := len(b.cycles); n > 0 && b.cycles[n-1].kind == busInternal {
+		b.cycles[n-1] = c
+	}
+}
+
+// Runs the provided test case, whose opcode was already fetched, along with
+// the prefix for CB-prefixed instructions, and returns the mismatches with its
+// final state.
+func runSingleStep(tc *singleStepCase, prefixed bool, opcode uint8) ([]string, error) {
+	tb := &testBus{}
+	cpu := New()
+	cpu.bus = tb
+
+	// The vectors expect the opcode to be prefetched, and end with the fetch
+	// of the next one. The CPU fetches the opcode as part of the instruction
+	// instead, so it is run from one byte earlier.
+	in := tc.Initial
+	if prefixed {
+		tb.mem[in.PC-1] = 0xCB
+		tb.mem[in.PC] = opcode
+	} else {
+		tb.mem[in.PC-1] = opcode
+	}
+	for _, kv := range in.RAM {
+		tb.mem[kv[0]] = uint8(kv[1])
+	}
+	if in.IE != nil {
+		tb.mem[0xFFFF] = *in.IE
+	}
+
+	cpu.r.SetAF(uint16(in.A)<<8 | uint16(in.F))
+	cpu.r.SetPaired(RegisterBC, uint16(in.B)<<8|uint16(in.C))
+	cpu.r.SetPaired(RegisterDE, uint16(in.D)<<8|uint16(in.E))
+	cpu.r.SetPaired(RegisterHL, uint16(in.H)<<8|uint16(in.L))
+	*cpu.r.StackPointer() = in.SP
+	*cpu.r.ProgramCounter() = in.PC - 1
+	cpu.ime = in.IME != 0
+
+	cpu.execute()
+
+	var mismatches []string
+	mismatch := func(format string, args ...interface{}) {
+		mismatches = append(mismatches, fmt.Sprintf(format, args...))
+	}
+
+	// Taking the state reads memory through the bus.
+	cycles := append([]busCycle(nil), tb.cycles...)
+
+	out := tc.Final
+	got := cpu.State()
+	for _, r := range []struct {
+		name          string
+		got, expected uint16
+	}{
+		{"A", uint16(got.A), uint16(out.A)},
+		{"F", uint16(got.F), uint16(out.F)},
+		{"B", uint16(got.B), uint16(out.B)},
+		{"C", uint16(got.C), uint16(out.C)},
+		{"D", uint16(got.D), uint16(out.D)},
+		{"E", uint16(got.E), uint16(out.E)},
+		{"H", uint16(got.H), uint16(out.H)},
+		{"L", uint16(got.L), uint16(out.L)},
+		{"SP", got.SP, out.SP},
+		{"PC", got.PC, out.PC - 1},
+	} {
+		if r.got != r.expected {
+			mismatch("%s: got 0x%02X, expected 0x%02X", r.name, r.got, r.expected)
+		}
+	}
+
+	// EI only sets IME after the following instruction, which the vectors do
+	// not run.
+	if ime := cpu.ime; opcode != 0xFB && ime != (out.IME != 0) {
+		mismatch("IME: got %t, expected %t", ime, out.IME != 0)
+	}
+
+	for _, kv := range out.RAM {
+		if b := tb.mem[kv[0]]; b != uint8(kv[1]) {
+			mismatch("RAM[0x%04X]: got 0x%02X, expected 0x%02X", kv[0], b, kv[1])
+		}
+	}
+
+	if len(cycles) != len(tc.Cycles) {
+		mismatch("cycles: got %d, expected %d", len(cycles), len(tc.Cycles))
+		return mismatches, nil
+	}
+
+	// The first cycle of the CPU fetches the opcode, which the vectors do
+	// not cover, and their last one fetches the next opcode.
+	for i := 1; i < len(cycles); i++ {
+		expected, err := decodeBusCycle(tc.Cycles[i-1])
+		if err != nil {
+			return nil, err
+		}
+		if c := cycles[i]; c != expected {
+			mismatch("cycle %d: got %v, expected %v", i, c, expected)
+		}
+	}
+
+	return mismatches, nil
+}
+
+// Runs every case of the provided test vector file.
+func testSingleStep(t *testing.T, path string, prefixed bool, opcode uint8) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Skipf("%s not found", path)
+	}
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	var cases []singleStepCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	failed := 0
+	for i := range cases {
+		mismatches, err := runSingleStep(&cases[i], prefixed, opcode)
+		if err != nil {
+			t.Fatalf("%s: %v", cases[i].Name, err)
+		}
+		if len(mismatches) == 0 {
+			continue
+		}
+
+		if failed++; failed <= singleStepMaxErrors {
+			t.Errorf("%s:\n\t%s", cases[i].Name, strings.Join(mismatches, "\n\t"))
+		}
+	}
+
+	if failed > singleStepMaxErrors {
+		t.Errorf("%d of %d cases failed", failed, len(cases))
+	}
+}
+
+func TestSingleStep(t *testing.T) {
+	if _, err := os.Stat(singleStepDir); os.IsNotExist(err) {
+		t.Skipf("%s not found", singleStepDir)
+	}
+
+	for _, i := range instructions {
+		opcode := i.opcode
+
+		// HALT and STOP depend on the rest of the system, and the illegal
+		// opcodes lock the CPU up. The prefix is covered by the CB-prefixed
+		// instructions.
+		switch {
+		case opcode == 0x10, opcode == 0x76, opcode == 0xCB, i.mnemonic == "ILLEGAL":
+			continue
+		}
+
+		t.Run(fmt.Sprintf("opcode=0x%02X mnemonic=%s", opcode, i.mnemonic), func(t *testing.T) {
+			path := filepath.Join(singleStepDir, fmt.Sprintf("%02x.json", opcode))
+			testSingleStep(t, path, false, opcode)
+		})
+	}
+
+	for _, i := range instructionsCB {
+		opcode := i.opcode
+
+		t.Run(fmt.Sprintf("opcode=0xCB%02X mnemonic=%s", opcode, i.mnemonic), func(t *testing.T) {
+			path := filepath.Join(singleStepDir, fmt.Sprintf("cb %02x.json", opcode))
+			testSingleStep(t, path, true, opcode)
+		})
+	}
+}
+
+func TestRunSingleStep(t *testing.T) {
+	var testCases = []struct {
+		opcode   uint8
+		prefixed bool
+		vector   string
+	}{
+		// NOP
+		{0x00, false, `{"name": "00", "initial": {"pc": 257, "sp": 0, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 0]]},
+			"final": {"pc": 258, "sp": 0, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 0]]},
+			"cycles": [[257, 0, "r-m"]]}`},
+		// JR 2
+		{0x18, false, `{"name": "18", "initial": {"pc": 257, "sp": 0, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 24], [257, 2]]},
+			"final": {"pc": 261, "sp": 0, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 24], [257, 2]]},
+			"cycles": [[257, 2, "r-m"], null, [260, 0, "r-m"]]}`},
+		// DEC B, with the carry flag left untouched.
+		{0x05, false, `{"name": "05", "initial": {"pc": 257, "sp": 0, "a": 0, "b": 16, "c": 0, "d": 0, "e": 0, "f": 16, "h": 0, "l": 0, "ime": 0, "ram": [[256, 5]]},
+			"final": {"pc": 258, "sp": 0, "a": 0, "b": 15, "c": 0, "d": 0, "e": 0, "f": 112, "h": 0, "l": 0, "ime": 0, "ram": [[256, 5]]},
+			"cycles": [[257, 0, "r-m"]]}`},
+		// SUB 1
+		{0xD6, false, `{"name": "d6", "initial": {"pc": 257, "sp": 0, "a": 16, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 214], [257, 1]]},
+			"final": {"pc": 259, "sp": 0, "a": 15, "b": 0, "c": 0, "d": 0, "e": 0, "f": 96, "h": 0, "l": 0, "ime": 0, "ram": [[256, 214], [257, 1]]},
+			"cycles": [[257, 1, "r-m"], [258, 0, "r-m"]]}`},
+		// ADD SP,1
+		{0xE8, false, `{"name": "e8", "initial": {"pc": 257, "sp": 255, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 232], [257, 1]]},
+			"final": {"pc": 259, "sp": 256, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 48, "h": 0, "l": 0, "ime": 0, "ram": [[256, 232], [257, 1]]},
+			"cycles": [[257, 1, "r-m"], null, null, [258, 0, "r-m"]]}`},
+		// PUSH BC
+		{0xC5, false, `{"name": "c5", "initial": {"pc": 257, "sp": 512, "a": 0, "b": 18, "c": 52, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 197]]},
+			"final": {"pc": 258, "sp": 510, "a": 0, "b": 18, "c": 52, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 197], [511, 18], [510, 52]]},
+			"cycles": [null, [511, 18, "-wm"], [510, 52, "-wm"], [257, 0, "r-m"]]}`},
+		// RL B, shifting in a reset carry flag.
+		{0x10, true, `{"name": "cb 10", "initial": {"pc": 257, "sp": 0, "a": 0, "b": 129, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 203], [257, 16]]},
+			"final": {"pc": 259, "sp": 0, "a": 0, "b": 2, "c": 0, "d": 0, "e": 0, "f": 16, "h": 0, "l": 0, "ime": 0, "ram": [[256, 203], [257, 16]]},
+			"cycles": [[257, 16, "r-m"], [258, 0, "r-m"]]}`},
+		// SBC A,0, with the incoming carry causing the half borrow.
+		{0xDE, false, `{"name": "de", "initial": {"pc": 257, "sp": 0, "a": 16, "b": 0, "c": 0, "d": 0, "e": 0, "f": 16, "h": 0, "l": 0, "ime": 0, "ram": [[256, 222], [257, 0]]},
+			"final": {"pc": 259, "sp": 0, "a": 15, "b": 0, "c": 0, "d": 0, "e": 0, "f": 96, "h": 0, "l": 0, "ime": 0, "ram": [[256, 222], [257, 0]]},
+			"cycles": [[257, 0, "r-m"], [258, 0, "r-m"]]}`},
+		// SBC A,(HL), with the incoming carry causing the half borrow.
+		{0x9E, false, `{"name": "9e", "initial": {"pc": 257, "sp": 0, "a": 32, "b": 0, "c": 0, "d": 0, "e": 0, "f": 16, "h": 2, "l": 0, "ime": 0, "ram": [[256, 158], [512, 15]]},
+			"final": {"pc": 258, "sp": 0, "a": 16, "b": 0, "c": 0, "d": 0, "e": 0, "f": 96, "h": 2, "l": 0, "ime": 0, "ram": [[256, 158], [512, 15]]},
+			"cycles": [[512, 15, "r-m"], [257, 0, "r-m"]]}`},
+		// CP 0x41, borrowing from both nibbles.
+		{0xFE, false, `{"name": "fe", "initial": {"pc": 257, "sp": 0, "a": 48, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 254], [257, 65]]},
+			"final": {"pc": 259, "sp": 0, "a": 48, "b": 0, "c": 0, "d": 0, "e": 0, "f": 112, "h": 0, "l": 0, "ime": 0, "ram": [[256, 254], [257, 65]]},
+			"cycles": [[257, 65, "r-m"], [258, 0, "r-m"]]}`},
+		// LD HL,SP+1, with its internal cycle.
+		{0xF8, false, `{"name": "f8", "initial": {"pc": 257, "sp": 255, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 248], [257, 1]]},
+			"final": {"pc": 259, "sp": 255, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 48, "h": 1, "l": 0, "ime": 0, "ram": [[256, 248], [257, 1]]},
+			"cycles": [[257, 1, "r-m"], null, [258, 0, "r-m"]]}`},
+		// POP AF, with the low nibble of F masked.
+		{0xF1, false, `{"name": "f1", "initial": {"pc": 257, "sp": 512, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 241], [512, 255], [513, 18]]},
+			"final": {"pc": 258, "sp": 514, "a": 18, "b": 0, "c": 0, "d": 0, "e": 0, "f": 240, "h": 0, "l": 0, "ime": 0, "ram": [[256, 241], [512, 255], [513, 18]]},
+			"cycles": [[512, 255, "r-m"], [513, 18, "r-m"], [257, 0, "r-m"]]}`},
+		// RR B, setting the zero flag.
+		{0x18, true, `{"name": "cb 18", "initial": {"pc": 257, "sp": 0, "a": 0, "b": 1, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0, "ime": 0, "ram": [[256, 203], [257, 24]]},
+			"final": {"pc": 259, "sp": 0, "a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 144, "h": 0, "l": 0, "ime": 0, "ram": [[256, 203], [257, 24]]},
+			"cycles": [[257, 24, "r-m"], [258, 0, "r-m"]]}`},
+	}
+
+	for _, tc := range testCases {
+		var v singleStepCase
+		if err := json.Unmarshal([]byte(tc.vector), &v); err != nil {
+			t.Fatalf("got %v, expected nil", err)
+		}
+
+		t.Run(v.Name, func(t *testing.T) {
+			mismatches, err := runSingleStep(&v, tc.prefixed, tc.opcode)
+			if err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+			for _, m := range mismatches {
+				t.Error(m)
+			}
+		})
+	}
+}
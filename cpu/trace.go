@@ -0,0 +1,92 @@
+package cpu
+
+import (
+	"fmt"
+	"io"
+)
+
+// State is a snapshot of the registers of the CPU, along with the 4 bytes of
+// memory starting at the program counter.
+type State struct {
+	A, F, B, C, D, E, H, L uint8
+	SP, PC                 uint16
+	PCMem                  [4]uint8
+}
+
+// String returns the state in the format of gameboy-doctor logs, e.g.
+// "A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,C3,13,02".
+func (s State) String() string {
+	return fmt.Sprintf("A:%02X F:%02X B:%02X C:%02X D:%02X E:%02X H:%02X L:%02X SP:%04X PC:%04X PCMEM:%02X,%02X,%02X,%02X",
+		s.A, s.F, s.B, s.C, s.D, s.E, s.H, s.L, s.SP, s.PC,
+		s.PCMem[0], s.PCMem[1], s.PCMem[2], s.PCMem[3])
+}
+
+// State returns a snapshot of the registers of the CPU. Reading the memory at
+// the program counter does not advance the rest of the system.
+func (cpu *CPU) State() State {
+	bc, _ := cpu.r.Paired(RegisterBC)
+	de, _ := cpu.r.Paired(RegisterDE)
+	hl, _ := cpu.r.Paired(RegisterHL)
+
+	s := State{
+		A:  *cpu.r.Accumulator(),
+		F:  uint8(cpu.r.AF()),
+		B:  uint8(bc >> 8),
+		C:  uint8(bc),
+		D:  uint8(de >> 8),
+		E:  uint8(de),
+		H:  uint8(hl >> 8),
+		L:  uint8(hl),
+		SP: *cpu.r.StackPointer(),
+		PC: *cpu.r.ProgramCounter(),
+	}
+
+	for i := range s.PCMem {
+		s.PCMem[i] = cpu.bus.Load(s.PC + uint16(i))
+	}
+
+	return s
+}
+
+// Tracer is handed the state of the CPU right before every instruction it
+// executes. Interrupts being serviced and machine cycles spent halted or
+// stopped are not traced.
+type Tracer interface {
+	Trace(s State)
+}
+
+// WithTracer makes the CPU hand its state over to the provided tracer before
+// every instruction.
+func WithTracer(t Tracer) Option {
+	return func(cpu *CPU) {
+		cpu.tracer = t
+	}
+}
+
+// LogTracer is a Tracer writing one line per instruction in the format of
+// gameboy-doctor logs, so that runs can be compared line by line against logs
+// of other emulators.
+type LogTracer struct {
+	w   io.Writer
+	err error
+}
+
+// NewLogTracer returns a new LogTracer writing to the provided writer.
+func NewLogTracer(w io.Writer) *LogTracer {
+	return &LogTracer{w: w}
+}
+
+// Trace writes a line holding the provided state. Nothing is written once a
+// write has failed.
+func (t *LogTracer) Trace(s State) {
+	if t.err != nil {
+		return
+	}
+
+	_, t.err = fmt.Fprintln(t.w, s)
+}
+
+// Err returns the error the first failed write returned, if any.
+func (t *LogTracer) Err() error {
+	return t.err
+}
@@ -0,0 +1,70 @@
+package cpu
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/loizoskounios/game-boy-emulator/mmu"
+)
+
+func TestStateString(t *testing.T) {
+	cpu := New(SkipBoot(mmu.ModelDMG))
+	for i, b := range []uint8{0x00, 0xC3, 0x13, 0x02} {
+		cpu.mmu.Store(0xC000+uint16(i), b)
+	}
+	*cpu.r.ProgramCounter() = 0xC000
+
+	expected := "A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:C000 PCMEM:00,C3,13,02"
+	if s := cpu.State().String(); s != expected {
+		t.Errorf("got %q, expected %q", s, expected)
+	}
+}
+
+func TestLogTracer(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := NewLogTracer(&buf)
+
+	// LD A,0x12, INC B
+	cpu := newProgram(0x3E, 0x12, 0x04)
+	cpu.tracer = tracer
+	cpu.Step()
+	cpu.Step()
+
+	expected := "A:00 F:00 B:00 C:00 D:00 E:00 H:00 L:00 SP:D000 PC:C000 PCMEM:3E,12,04,00\n" +
+		"A:12 F:00 B:00 C:00 D:00 E:00 H:00 L:00 SP:D000 PC:C002 PCMEM:04,00,00,00\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+	if err := tracer.Err(); err != nil {
+		t.Errorf("got %v, expected nil", err)
+	}
+}
+
+type failingWriter struct {
+	writes int
+}
+
+var errWrite = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errWrite
+}
+
+func TestLogTracerError(t *testing.T) {
+	w := &failingWriter{}
+	tracer := NewLogTracer(w)
+
+	cpu := newProgram(0x00, 0x00)
+	cpu.tracer = tracer
+	cpu.Step()
+	cpu.Step()
+
+	if err := tracer.Err(); err != errWrite {
+		t.Errorf("got %v, expected %v", err, errWrite)
+	}
+	if w.writes != 1 {
+		t.Errorf("writes: got %d, expected 1", w.writes)
+	}
+}
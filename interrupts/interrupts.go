@@ -0,0 +1,123 @@
+// Package interrupts implements the Game Boy interrupt controller, made up of
+// the interrupt enable (IE) and interrupt flag (IF) registers.
+package interrupts
+
+// Interrupt is the type for our interrupt enumeration.
+type Interrupt uint8
+
+// Enumerates the interrupts, in order of priority. Each one is represented by
+// the bit of IE and IF with the same number.
+const (
+	VBlank Interrupt = iota
+	LCDStat
+	Timer
+	Serial
+	Joypad
+)
+
+func (i Interrupt) String() string {
+	switch i {
+	case VBlank:
+		return "VBlank"
+	case LCDStat:
+		return "LCD STAT"
+	case Timer:
+		return "Timer"
+	case Serial:
+		return "Serial"
+	case Joypad:
+		return "Joypad"
+	default:
+		return "unknown"
+	}
+}
+
+// Vector returns the address the CPU jumps to when servicing the interrupt.
+func (i Interrupt) Vector() uint16 {
+	return 0x40 + 8*uint16(i)
+}
+
+// Returns the bit of IE and IF that represents the interrupt.
+func (i Interrupt) mask() uint8 {
+	return 1 << i
+}
+
+// Addresses of the interrupt registers.
+const (
+	AddressIF uint16 = 0xFF0F
+	AddressIE uint16 = 0xFFFF
+)
+
+// Only the lower 5 bits of IF exist. The upper 3 bits read as 1.
+const (
+	flagBits   uint8 = 0x1F
+	unusedBits uint8 = 0xE0
+)
+
+// Controller is the interrupt controller. Components request interrupts by
+// setting their bit in IF, and the CPU services the enabled ones in order of
+// priority.
+type Controller struct {
+	enable uint8
+	flag   uint8
+}
+
+// New returns a pointer to a new interrupt controller, with no interrupt
+// enabled or requested.
+func New() *Controller {
+	return &Controller{}
+}
+
+// Reset disables and clears all interrupts.
+func (c *Controller) Reset() {
+	c.enable = 0
+	c.flag = 0
+}
+
+// Request requests the provided interrupt by setting its bit in IF.
+func (c *Controller) Request(i Interrupt) {
+	c.flag |= i.mask()
+}
+
+// Acknowledge clears the bit of the provided interrupt in IF, as the CPU does
+// when servicing it.
+func (c *Controller) Acknowledge(i Interrupt) {
+	c.flag &^= i.mask()
+}
+
+// Pending returns the interrupt with the highest priority among the ones that
+// are both enabled and requested. The boolean is false if there is none.
+func (c *Controller) Pending() (Interrupt, bool) {
+	pending := c.enable & c.flag & flagBits
+	for i := VBlank; i <= Joypad; i++ {
+		if pending&i.mask() != 0 {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// Load returns the contents of the register at the provided address.
+func (c *Controller) Load(addr uint16) uint8 {
+	switch addr {
+	case AddressIF:
+		return c.flag | unusedBits
+	case AddressIE:
+		return c.enable
+	default:
+		return 0xFF
+	}
+}
+
+// Store saves the provided value into the register at the provided address.
+// All 8 bits of IE can be written to, although only the lower 5 have an
+// effect.
+func (c *Controller) Store(addr uint16, b uint8) {
+	switch addr {
+	case AddressIF:
+		c.flag = b & flagBits
+	case AddressIE:
+		c.enable = b
+	}
+}
@@ -0,0 +1,58 @@
+package interrupts
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPending(t *testing.T) {
+	var testCases = []struct {
+		enable, flag uint8
+		expected     Interrupt
+		ok           bool
+	}{
+		{0x00, 0x1F, 0, false},
+		{0x1F, 0x00, 0, false},
+		{0x1F, 0x1F, VBlank, true},
+		{0x1E, 0x1F, LCDStat, true},
+		{0x14, 0x1C, Timer, true},
+		{0xFF, 0x10, Joypad, true},
+		{0xE0, 0xFF, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("IE=0x%02X IF=0x%02X", tc.enable, tc.flag), func(t *testing.T) {
+			c := New()
+			c.Store(AddressIE, tc.enable)
+			c.Store(AddressIF, tc.flag)
+
+			i, ok := c.Pending()
+			if ok != tc.ok || i != tc.expected {
+				t.Errorf("got %s (%t), expected %s (%t)", i, ok, tc.expected, tc.ok)
+			}
+		})
+	}
+}
+
+func TestRequest(t *testing.T) {
+	c := New()
+
+	c.Request(Timer)
+	c.Request(Joypad)
+	if b := c.Load(AddressIF); b != 0xF4 {
+		t.Errorf("got 0x%02X, expected 0xF4", b)
+	}
+
+	c.Acknowledge(Timer)
+	if b := c.Load(AddressIF); b != 0xF0 {
+		t.Errorf("got 0x%02X, expected 0xF0", b)
+	}
+}
+
+func TestVector(t *testing.T) {
+	for i, expected := range []uint16{0x40, 0x48, 0x50, 0x58, 0x60} {
+		if v := Interrupt(i).Vector(); v != expected {
+			t.Errorf("%s: got 0x%02X, expected 0x%02X", Interrupt(i), v, expected)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package joypad implements the Game Boy joypad: the P1 register, through
+// which the buttons are read, and the joypad interrupt.
+package joypad
+
+import "github.com/loizoskounios/game-boy-emulator/interrupts"
+
+// AddressP1 is the address of the joypad register.
+const AddressP1 uint16 = 0xFF00
+
+// Button is the type for our joypad button enumeration.
+type Button uint8
+
+// Enumerates the joypad buttons. The direction keys are read through the
+// lower nibble of P1 when bit 4 is reset, and the other buttons when bit 5 is
+// reset, in this order.
+const (
+	Right Button = iota
+	Left
+	Up
+	Down
+	A
+	B
+	Select
+	Start
+)
+
+func (b Button) String() string {
+	switch b {
+	case Right:
+		return "Right"
+	case Left:
+		return "Left"
+	case Up:
+		return "Up"
+	case Down:
+		return "Down"
+	case A:
+		return "A"
+	case B:
+		return "B"
+	case Select:
+		return "Select"
+	case Start:
+		return "Start"
+	default:
+		return "unknown"
+	}
+}
+
+// Bits of P1 that select which buttons are read when reset.
+const (
+	selectDirections uint8 = 1 << 4
+	selectButtons    uint8 = 1 << 5
+	selectBits             = selectDirections | selectButtons
+)
+
+// Joypad is the Game Boy joypad. A joypad interrupt is requested whenever one
+// of the lines read through P1 goes low, either because a button was pressed
+// or because its group was selected.
+type Joypad struct {
+	// pressed holds a bit per Button, set while the button is held down.
+	pressed uint8
+	// selection holds bits 4 and 5 of P1.
+	selection uint8
+
+	interrupts *interrupts.Controller
+}
+
+// New returns a pointer to a new joypad that requests its interrupt through
+// the provided interrupt controller.
+func New(ic *interrupts.Controller) *Joypad {
+	return &Joypad{selection: selectBits, interrupts: ic}
+}
+
+// Reset releases all buttons and deselects both groups.
+func (j *Joypad) Reset() {
+	j.pressed = 0
+	j.selection = selectBits
+}
+
+// Returns the lines read through the lower nibble of P1, with a bit set for
+// every line pulled low by a pressed button in a selected group.
+func (j *Joypad) lines() uint8 {
+	var lines uint8
+	if j.selection&selectDirections == 0 {
+		lines |= j.pressed & 0x0F
+	}
+	if j.selection&selectButtons == 0 {
+		lines |= j.pressed >> 4
+	}
+
+	return lines
+}
+
+// Changes the state of the joypad through f, requesting an interrupt if a
+// line went low as a result.
+func (j *Joypad) update(f func()) {
+	before := j.lines()
+	f()
+	if j.lines()&^before != 0 {
+		j.interrupts.Request(interrupts.Joypad)
+	}
+}
+
+// Press holds the provided button down.
+func (j *Joypad) Press(b Button) {
+	j.update(func() { j.pressed |= 1 << b })
+}
+
+// Release lets go of the provided button.
+func (j *Joypad) Release(b Button) {
+	j.update(func() { j.pressed &^= 1 << b })
+}
+
+// Active returns whether a pressed button is pulling one of the lines read
+// through P1 low, which is what wakes the CPU up from STOP.
+func (j *Joypad) Active() bool {
+	return j.lines() != 0
+}
+
+// Load returns the contents of P1. The lines are active low, and the unused
+// upper 2 bits read as 1.
+func (j *Joypad) Load(addr uint16) uint8 {
+	if addr != AddressP1 {
+		return 0xFF
+	}
+
+	return 0xC0 | j.selection | ^j.lines()&0x0F
+}
+
+// Store selects the groups of buttons read through P1.
+func (j *Joypad) Store(addr uint16, b uint8) {
+	if addr != AddressP1 {
+		return
+	}
+
+	j.update(func() { j.selection = b & selectBits })
+}
@@ -0,0 +1,65 @@
+package joypad
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/loizoskounios/game-boy-emulator/interrupts"
+)
+
+func TestLoad(t *testing.T) {
+	var testCases = []struct {
+		pressed  []Button
+		p1       uint8
+		expected uint8
+	}{
+		{nil, 0x00, 0xC0 | 0x0F},
+		{[]Button{Right, Start}, 0x30, 0xF0 | 0x0F},
+		{[]Button{Right, Start}, 0x20, 0xE0 | 0x0E},
+		{[]Button{Right, Start}, 0x10, 0xD0 | 0x07},
+		{[]Button{Right, Start}, 0x00, 0xC0 | 0x06},
+		{[]Button{Down, B}, 0x10, 0xD0 | 0x0D},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("pressed=%v P1=0x%02X", tc.pressed, tc.p1), func(t *testing.T) {
+			j := New(interrupts.New())
+			for _, b := range tc.pressed {
+				j.Press(b)
+			}
+			j.Store(AddressP1, tc.p1)
+
+			if p1 := j.Load(AddressP1); p1 != tc.expected {
+				t.Errorf("got 0x%02X, expected 0x%02X", p1, tc.expected)
+			}
+		})
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	ic := interrupts.New()
+	ic.Store(interrupts.AddressIE, 0xFF)
+	j := New(ic)
+
+	// Pressing a button of a group that is not selected does nothing.
+	j.Store(AddressP1, 0x20)
+	j.Press(A)
+	if _, ok := ic.Pending(); ok {
+		t.Error("got interrupt requested, expected none")
+	}
+
+	// Selecting the group pulls the line low.
+	j.Store(AddressP1, 0x10)
+	if i, ok := ic.Pending(); !ok || i != interrupts.Joypad {
+		t.Errorf("got %s (%t), expected %s", i, ok, interrupts.Joypad)
+	}
+	if !j.Active() {
+		t.Error("got inactive, expected active")
+	}
+
+	ic.Acknowledge(interrupts.Joypad)
+	j.Release(A)
+	if _, ok := ic.Pending(); ok {
+		t.Error("release: got interrupt requested, expected none")
+	}
+}
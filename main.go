@@ -1,9 +1,197 @@
 package main
 
-import "github.com/loizoskounios/game-boy-emulator/cpu"
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/loizoskounios/game-boy-emulator/cartridge"
+	"github.com/loizoskounios/game-boy-emulator/cpu"
+	"github.com/loizoskounios/game-boy-emulator/mmu"
+	"github.com/loizoskounios/game-boy-emulator/patch"
+	"github.com/loizoskounios/game-boy-emulator/ppu"
+)
+
+// Returns the hardware model with the provided name.
+func parseModel(name string) (mmu.Model, error) {
+	for _, model := range []mmu.Model{mmu.ModelDMG, mmu.ModelMGB, mmu.ModelSGB, mmu.ModelCGB} {
+		if strings.EqualFold(name, model.String()) {
+			return model, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown model %q", name)
+}
+
+// Reads the ROM image stored in romPath, applies the patch stored in
+// patchPath to it if provided, and inserts a cartridge holding the result.
+// Neither file is modified.
+func loadROM(mmu *mmu.MemoryManagementUnit, romPath, patchPath string) error {
+	if patchPath == "" {
+		return mmu.LoadROMFile(romPath)
+	}
+
+	rom, err := os.ReadFile(romPath)
+	if err != nil {
+		return err
+	}
+
+	p, err := os.ReadFile(patchPath)
+	if err != nil {
+		return err
+	}
+
+	patched, err := patch.Apply(rom, p)
+	if err != nil {
+		return fmt.Errorf("%s: %w", patchPath, err)
+	}
+
+	return mmu.LoadROM(patched, cartridge.IgnoreGlobalChecksum())
+}
+
+// Periodically writes the save data of the cartridge to disk until the
+// provided context is done.
+func persist(ctx context.Context, saver *cartridge.Saver) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := saver.MaybeFlush(); err != nil {
+				log.Print(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Writes the provided frame to path as a PNG image.
+func screenshot(path string, frame *ppu.Frame) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, frame.Image()); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
 
 func main() {
-	cpu := cpu.New()
+	header := flag.Bool("header", false, "print the cartridge header and exit")
+	patchPath := flag.String("patch", "", "IPS, UPS or BPS `file` to apply to the ROM in memory, keeping the save file next to it")
+	skipBoot := flag.Bool("skip-boot", false, "skip the boot ROM and start at 0x0100")
+	modelName := flag.String("model", "dmg", "hardware model whose post-boot state is used with -skip-boot (dmg, mgb, sgb, cgb)")
+	tracePath := flag.String("trace", "", "write a gameboy-doctor log line for every executed instruction to `file`")
+	screenshotPath := flag.String("screenshot", "", "write the last frame drawn to `file` as a PNG image on exit")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <rom>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	mmu := mmu.New()
+	if err := loadROM(mmu, flag.Arg(0), *patchPath); err != nil {
+		log.Fatal(err)
+	}
+
+	if *header {
+		fmt.Println(mmu.Header())
+		return
+	}
+
+	opts := []cpu.Option{cpu.WithMMU(mmu)}
+	if *skipBoot {
+		model, err := parseModel(*modelName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, cpu.SkipBoot(model))
+	}
+
+	var trace *bufio.Writer
+	if *tracePath != "" {
+		f, err := os.Create(*tracePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+
+		trace = bufio.NewWriter(f)
+		opts = append(opts, cpu.WithTracer(cpu.NewLogTracer(trace)))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var (
+		saver *cartridge.Saver
+		wg    sync.WaitGroup
+	)
+	if cart := mmu.Cartridge(); cart.Battery() {
+		// Patched games get a save file of their own, as they may lay out
+		// their save data differently from the original game.
+		savePath := cartridge.SavePath(flag.Arg(0))
+		if *patchPath != "" {
+			savePath = cartridge.SavePath(*patchPath)
+		}
+
+		saver = cartridge.NewSaver(cart, savePath, cartridge.DefaultFlushInterval)
+		if err := saver.Load(); err != nil {
+			log.Fatal(err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			persist(ctx, saver)
+		}()
+	}
+
+	cpu := cpu.New(opts...)
+
+	err := cpu.Run(ctx)
+	stop()
+	wg.Wait()
+
+	if saver != nil {
+		if err := saver.Flush(); err != nil {
+			log.Print(err)
+		}
+	}
+
+	if trace != nil {
+		if err := trace.Flush(); err != nil {
+			log.Print(err)
+		}
+	}
+
+	if *screenshotPath != "" {
+		if err := screenshot(*screenshotPath, mmu.PPU().Frame()); err != nil {
+			log.Print(err)
+		}
+	}
 
-	cpu.Dispatch()
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,75 @@
+package mmu
+
+// AddressDMA is the address of the register that starts an OAM DMA transfer
+// when written to. The value written is the upper byte of the source address.
+const AddressDMA uint16 = 0xFF46
+
+// dmaLength is the number of bytes copied by an OAM DMA transfer, one per
+// machine cycle.
+const dmaLength = 0xA0
+
+// dma copies 160 bytes from anywhere in memory into OAM, one byte per machine
+// cycle.
+type dma struct {
+	source uint16
+
+	// index is the index of the next byte to copy, or dmaLength when no
+	// transfer is running.
+	index int
+
+	// delay is the number of machine cycles left before a requested transfer
+	// starts copying.
+	delay int
+
+	// restarted is whether the requested transfer replaced one that was
+	// copying, in which case OAM stays blocked through the setup cycle.
+	restarted bool
+}
+
+func newDMA() dma {
+	return dma{index: dmaLength}
+}
+
+// Returns whether a transfer is copying bytes, during which the CPU cannot
+// access OAM.
+func (d *dma) active() bool {
+	return d.index < dmaLength && (d.delay == 0 || d.restarted)
+}
+
+// Requests a transfer from the provided page, restarting any transfer that is
+// running. The transfer starts copying after a machine cycle of setup.
+func (d *dma) start(page uint8) {
+	d.restarted = d.active()
+	d.source = uint16(page) << 8
+	d.index = 0
+	d.delay = 1
+}
+
+// DMAActive returns whether an OAM DMA transfer is copying bytes into OAM.
+func (mmu *MemoryManagementUnit) DMAActive() bool {
+	return mmu.dma.active()
+}
+
+// Advances the OAM DMA transfer by one machine cycle, copying a byte if it is
+// running.
+func (mmu *MemoryManagementUnit) tickDMA() {
+	d := &mmu.dma
+	if d.index >= dmaLength {
+		return
+	}
+
+	if d.delay > 0 {
+		d.delay--
+		return
+	}
+
+	// Pages 0xE0 to 0xFF are not mapped to OAM and I/O as seen by the CPU,
+	// but mirror working RAM instead.
+	src := d.source + uint16(d.index)
+	if src >= workingRAMShadow.start {
+		src -= workingRAMShadow.start - workingRAM.start
+	}
+
+	mmu.ppu.Store(spiteInfo.start+uint16(d.index), mmu.load(src))
+	d.index++
+}
@@ -1,5 +1,16 @@
 package mmu
 
+import (
+	"os"
+
+	"github.com/loizoskounios/game-boy-emulator/cartridge"
+	"github.com/loizoskounios/game-boy-emulator/interrupts"
+	"github.com/loizoskounios/game-boy-emulator/joypad"
+	"github.com/loizoskounios/game-boy-emulator/ppu"
+	"github.com/loizoskounios/game-boy-emulator/serial"
+	"github.com/loizoskounios/game-boy-emulator/timer"
+)
+
 // BIOS is an array holding all 256 instructions of the Game Boy BIOS.
 var BIOS = [256]uint8{
 	0x31, 0xFE, 0xFF, 0xAF, 0x21, 0xFF, 0x9F, 0x32,
@@ -36,6 +47,10 @@ var BIOS = [256]uint8{
 	0xFB, 0x86, 0x20, 0xFE, 0x3E, 0x11, 0xE0, 0x50,
 }
 
+// BootROMDisable is the address of the register that unmaps the boot ROM when
+// written to with a non-zero value.
+const BootROMDisable uint16 = 0xFF50
+
 type memoryRegion struct {
 	start uint16
 	end   uint16
@@ -59,24 +74,234 @@ var (
 // MemoryManagementUnit encompasses the functionality required of a Game Boy
 // memory management unit.
 type MemoryManagementUnit struct {
-	m *memory
+	m          *memory
+	cart       *cartridge.Cartridge
+	interrupts *interrupts.Controller
+	timer      *timer.Timer
+	joypad     *joypad.Joypad
+	serial     *serial.Serial
+	ppu        *ppu.PPU
+	dma        dma
+
+	// bootROMMapped is true while the boot ROM is overlaid on top of the first
+	// 256 bytes of the cartridge ROM.
+	bootROMMapped bool
 }
 
 // New returns a pointer a new memory management unit.
 func New() *MemoryManagementUnit {
-	m := newMemory()
-	for i := bios.start; i <= bios.end; i++ {
-		m[i] = BIOS[i]
+	ic := interrupts.New()
+
+	return &MemoryManagementUnit{
+		m:             newMemory(),
+		interrupts:    ic,
+		timer:         timer.New(ic),
+		joypad:        joypad.New(ic),
+		serial:        serial.New(ic),
+		ppu:           ppu.New(ic),
+		dma:           newDMA(),
+		bootROMMapped: true,
+	}
+}
+
+// Reset puts the memory management unit and the components attached to it
+// back in their power-up state, with the boot ROM mapped again. The inserted
+// cartridge is kept, and so is its battery-backed RAM if keepBatteryRAM is
+// true.
+func (mmu *MemoryManagementUnit) Reset(keepBatteryRAM bool) {
+	*mmu.m = memory{}
+	mmu.bootROMMapped = true
+	mmu.interrupts.Reset()
+	mmu.timer.Reset()
+	mmu.joypad.Reset()
+	mmu.serial.Reset()
+	mmu.ppu.Reset()
+	mmu.dma = newDMA()
+
+	if mmu.cart != nil {
+		mmu.cart.Reset(keepBatteryRAM)
+	}
+}
+
+// LoadROM validates the cartridge header of the provided ROM image and inserts
+// a cartridge holding the image, mapped into ROM banks 0 and 1 by the memory
+// bank controller the header asks for. The boot ROM remains overlaid on top of
+// the first 256 bytes of the image. Images with a malformed header or
+// mismatching checksums are rejected.
+func (mmu *MemoryManagementUnit) LoadROM(rom []uint8, opts ...cartridge.Option) error {
+	cart, err := cartridge.New(rom, opts...)
+	if err != nil {
+		return err
+	}
+
+	mmu.Insert(cart)
+
+	return nil
+}
+
+// Insert inserts the provided cartridge, replacing any previously inserted
+// one.
+func (mmu *MemoryManagementUnit) Insert(cart *cartridge.Cartridge) {
+	mmu.cart = cart
+}
+
+// LoadROMFile reads the cartridge ROM image stored in the provided file and
+// inserts a cartridge holding it.
+func (mmu *MemoryManagementUnit) LoadROMFile(path string, opts ...cartridge.Option) error {
+	rom, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return mmu.LoadROM(rom, opts...)
+}
+
+// Cartridge returns the inserted cartridge, or nil if there is none.
+func (mmu *MemoryManagementUnit) Cartridge() *cartridge.Cartridge {
+	return mmu.cart
+}
+
+// Header returns the cartridge header of the loaded ROM image, or nil if no
+// image has been loaded.
+func (mmu *MemoryManagementUnit) Header() *cartridge.Header {
+	if mmu.cart == nil {
+		return nil
 	}
-	return &MemoryManagementUnit{m: m}
+
+	return mmu.cart.Header
 }
 
-// Load returns the contents of memory at the provided address.
+// Interrupts returns the interrupt controller, through which components
+// request interrupts.
+func (mmu *MemoryManagementUnit) Interrupts() *interrupts.Controller {
+	return mmu.interrupts
+}
+
+// Timer returns the timer.
+func (mmu *MemoryManagementUnit) Timer() *timer.Timer {
+	return mmu.timer
+}
+
+// Joypad returns the joypad, through which buttons are pressed and released.
+func (mmu *MemoryManagementUnit) Joypad() *joypad.Joypad {
+	return mmu.joypad
+}
+
+// PPU returns the pixel processing unit, which holds the frames drawn.
+func (mmu *MemoryManagementUnit) PPU() *ppu.PPU {
+	return mmu.ppu
+}
+
+// Serial returns the serial port, through which the bytes sent over the link
+// cable can be captured.
+func (mmu *MemoryManagementUnit) Serial() *serial.Serial {
+	return mmu.serial
+}
+
+// Tick advances the components clocked by the system clock by one machine
+// cycle. The CPU calls it once for every machine cycle of an instruction,
+// before the memory access made during that cycle, if any.
+func (mmu *MemoryManagementUnit) Tick() {
+	mmu.timer.Tick()
+	mmu.serial.Tick()
+	mmu.ppu.Tick()
+	mmu.tickDMA()
+}
+
+// Load returns the contents of memory at the provided address. OAM reads as
+// 0xFF while an OAM DMA transfer is running. On the DMG, reads from anywhere
+// but HRAM also conflict with the transfer on the bus and see the byte being
+// copied instead; these conflicts are not emulated.
 func (mmu *MemoryManagementUnit) Load(addr uint16) uint8 {
+	if mmu.dma.active() && addr >= spiteInfo.start && addr <= spiteInfo.end {
+		return 0xFF
+	}
+
+	return mmu.load(addr)
+}
+
+// Returns the contents of memory at the provided address, regardless of any
+// OAM DMA transfer.
+func (mmu *MemoryManagementUnit) load(addr uint16) uint8 {
+	switch {
+	case mmu.bootROMMapped && addr <= bios.end:
+		return BIOS[addr]
+	case addr <= romBank1.end, addr >= externalRAM.start && addr <= externalRAM.end:
+		return mmu.loadCartridge(addr)
+	case addr == interrupts.AddressIF, addr == interrupts.AddressIE:
+		return mmu.interrupts.Load(addr)
+	case addr >= timer.AddressDIV && addr <= timer.AddressTAC:
+		return mmu.timer.Load(addr)
+	case addr == joypad.AddressP1:
+		return mmu.joypad.Load(addr)
+	case addr == serial.AddressSB, addr == serial.AddressSC:
+		return mmu.serial.Load(addr)
+	case isPPU(addr):
+		return mmu.ppu.Load(addr)
+	}
+
 	return mmu.m.Load(addr)
 }
 
-// Store saves the provided value into the provided address in memory.
+// Store saves the provided value into the provided address in memory. Writes
+// to the cartridge ROM and external RAM are handed over to the cartridge.
+// Writing a non-zero value to BootROMDisable unmaps the boot ROM for good,
+// exposing the first 256 bytes of the cartridge. Writing to AddressDMA starts
+// an OAM DMA transfer, during which writes to OAM are ignored. Bus conflicts
+// with the transfer elsewhere in memory are not emulated.
 func (mmu *MemoryManagementUnit) Store(addr uint16, b uint8) {
+	switch {
+	case mmu.dma.active() && addr >= spiteInfo.start && addr <= spiteInfo.end:
+		return
+	case addr <= romBank1.end, addr >= externalRAM.start && addr <= externalRAM.end:
+		if mmu.cart != nil {
+			mmu.cart.Store(addr, b)
+		}
+		return
+	case addr == interrupts.AddressIF, addr == interrupts.AddressIE:
+		mmu.interrupts.Store(addr, b)
+		return
+	case addr >= timer.AddressDIV && addr <= timer.AddressTAC:
+		mmu.timer.Store(addr, b)
+		return
+	case addr == joypad.AddressP1:
+		mmu.joypad.Store(addr, b)
+		return
+	case addr == serial.AddressSB, addr == serial.AddressSC:
+		mmu.serial.Store(addr, b)
+		return
+	case isPPU(addr):
+		mmu.ppu.Store(addr, b)
+		return
+	case addr == AddressDMA:
+		mmu.dma.start(b)
+	case addr == BootROMDisable && b != 0:
+		mmu.bootROMMapped = false
+	}
+
 	mmu.m.Store(addr, b)
 }
+
+// Returns whether the provided address belongs to video RAM, OAM or the LCD
+// registers, which OAM DMA sits in the middle of.
+func isPPU(addr uint16) bool {
+	return addr >= vram.start && addr <= vram.end ||
+		addr >= spiteInfo.start && addr <= spiteInfo.end ||
+		addr >= ppu.AddressLCDC && addr <= ppu.AddressWX && addr != AddressDMA
+}
+
+// BootROMMapped returns whether the boot ROM is still overlaid on top of the
+// cartridge ROM.
+func (mmu *MemoryManagementUnit) BootROMMapped() bool {
+	return mmu.bootROMMapped
+}
+
+// Returns the byte at the provided address of the cartridge. Without a
+// cartridge inserted, the data bus is left floating and reads as 0xFF.
+func (mmu *MemoryManagementUnit) loadCartridge(addr uint16) uint8 {
+	if mmu.cart == nil {
+		return 0xFF
+	}
+
+	return mmu.cart.Load(addr)
+}
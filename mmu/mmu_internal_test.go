@@ -1,12 +1,17 @@
 package mmu
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+
+	"github.com/loizoskounios/game-boy-emulator/cartridge"
+)
 
 func TestNew(t *testing.T) {
 	mmu := New()
 
 	for i := bios.start; i <= bios.end; i++ {
-		if val := mmu.m.Load(i); val != BIOS[i] {
+		if val := mmu.Load(i); val != BIOS[i] {
 			t.Errorf("got %d, expected %d", val, BIOS[i])
 		}
 	}
@@ -17,3 +22,198 @@ func TestNew(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadROM(t *testing.T) {
+	rom := cartridge.NewROM(0x00, 0x8000, 0x00)
+
+	mmu := New()
+	if err := mmu.LoadROM(rom); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	var testCases = []struct {
+		address uint16
+		out     uint8
+	}{
+		{0x0000, BIOS[0x0000]},
+		{0x00FF, BIOS[0x00FF]},
+		{0x0150, 0x00},
+		{0x3FFF, 0x00},
+		{0x4000, 0x01},
+		{0x7FFF, 0x01},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("address=0x%04X", tc.address), func(t *testing.T) {
+			if out := mmu.Load(tc.address); out != tc.out {
+				t.Errorf("got 0x%02X, expected 0x%02X", out, tc.out)
+			}
+		})
+	}
+}
+
+func TestLoadROMCorrupt(t *testing.T) {
+	rom := cartridge.NewROM(0x00, 0x8000, 0x00)
+	rom[0x4000]++
+
+	mmu := New()
+	if err := mmu.LoadROM(rom); err == nil {
+		t.Error("got nil, expected error")
+	}
+
+	if mmu.Header() != nil {
+		t.Errorf("got %v, expected nil", mmu.Header())
+	}
+}
+
+func TestStoreROM(t *testing.T) {
+	mmu := New()
+	mmu.LoadROM(cartridge.NewROM(0x00, 0x8000, 0x00))
+
+	mmu.Store(0x4000, 0xAB)
+	if val := mmu.Load(0x4000); val != 0x01 {
+		t.Errorf("got 0x%02X, expected 0x01", val)
+	}
+}
+
+func TestBootROMDisable(t *testing.T) {
+	mmu := New()
+	mmu.LoadROM(cartridge.NewROM(0x00, 0x8000, 0x00))
+
+	var testCases = []struct {
+		b      uint8
+		mapped bool
+		out    uint8
+	}{
+		{0x00, true, BIOS[0x0000]},
+		{0x01, false, 0x00},
+		{0x00, false, 0x00},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("b=0x%02X", tc.b), func(t *testing.T) {
+			mmu.Store(BootROMDisable, tc.b)
+
+			if mapped := mmu.BootROMMapped(); mapped != tc.mapped {
+				t.Errorf("got %t, expected %t", mapped, tc.mapped)
+			}
+
+			if out := mmu.Load(0x0000); out != tc.out {
+				t.Errorf("got 0x%02X, expected 0x%02X", out, tc.out)
+			}
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	rom := cartridge.NewROM(0x03, 0x8000, 0x02)
+
+	mmu := New()
+	if err := mmu.LoadROM(rom); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	mmu.Store(BootROMDisable, 0x01)
+	mmu.Store(0xC000, 0x12)
+	mmu.Store(0xFFFF, 0x1F)
+	mmu.Store(0x0000, 0x0A)
+	mmu.Store(0xA000, 0x34)
+
+	mmu.Reset(true)
+
+	var testCases = []struct {
+		address uint16
+		out     uint8
+	}{
+		{0x0000, BIOS[0x0000]},
+		{0xC000, 0x00},
+		{0xFFFF, 0x00},
+		{0xFF0F, 0xE0},
+		{0xA000, 0xFF},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("address=0x%04X", tc.address), func(t *testing.T) {
+			if out := mmu.Load(tc.address); out != tc.out {
+				t.Errorf("got 0x%02X, expected 0x%02X", out, tc.out)
+			}
+		})
+	}
+
+	// The battery-backed RAM survives the reset.
+	mmu.Store(0x0000, 0x0A)
+	if out := mmu.Load(0xA000); out != 0x34 {
+		t.Errorf("got 0x%02X, expected 0x34", out)
+	}
+}
+
+func TestDMA(t *testing.T) {
+	mmu := New()
+	for i := uint16(0); i < dmaLength; i++ {
+		mmu.Store(0xC100+i, uint8(i))
+	}
+	mmu.Store(spiteInfo.start, 0xAB)
+
+	mmu.Store(AddressDMA, 0xC1)
+	if mmu.DMAActive() {
+		t.Fatalf("got active before the setup cycle, expected inactive")
+	}
+
+	mmu.Tick()
+	if !mmu.DMAActive() {
+		t.Fatalf("got inactive after the setup cycle, expected active")
+	}
+	if b := mmu.Load(spiteInfo.start); b != 0xFF {
+		t.Errorf("OAM during transfer: got 0x%02X, expected 0xFF", b)
+	}
+
+	for i := 0; i < dmaLength; i++ {
+		mmu.Tick()
+	}
+	if mmu.DMAActive() {
+		t.Fatalf("got active after %d bytes, expected inactive", dmaLength)
+	}
+
+	for i := uint16(0); i < dmaLength; i++ {
+		if b := mmu.Load(spiteInfo.start + i); b != uint8(i) {
+			t.Errorf("OAM[%d]: got %d, expected %d", i, b, i)
+		}
+	}
+	if b := mmu.Load(AddressDMA); b != 0xC1 {
+		t.Errorf("DMA: got 0x%02X, expected 0xC1", b)
+	}
+}
+
+func TestDMARestart(t *testing.T) {
+	mmu := New()
+	for i := uint16(0); i < dmaLength; i++ {
+		mmu.Store(0xC100+i, uint8(i))
+		mmu.Store(0xC200+i, ^uint8(i))
+	}
+
+	mmu.Store(AddressDMA, 0xC1)
+	for i := 0; i < 11; i++ {
+		mmu.Tick()
+	}
+
+	// OAM stays blocked through the setup cycle of the new transfer.
+	mmu.Store(AddressDMA, 0xC2)
+	if !mmu.DMAActive() {
+		t.Fatalf("got inactive on restart, expected active")
+	}
+	if b := mmu.Load(spiteInfo.start); b != 0xFF {
+		t.Errorf("OAM on restart: got 0x%02X, expected 0xFF", b)
+	}
+
+	for i := 0; i < dmaLength+1; i++ {
+		mmu.Tick()
+	}
+	if mmu.DMAActive() {
+		t.Fatalf("got active after %d bytes, expected inactive", dmaLength)
+	}
+	for i := uint16(0); i < dmaLength; i++ {
+		if b := mmu.Load(spiteInfo.start + i); b != ^uint8(i) {
+			t.Errorf("OAM[%d]: got %d, expected %d", i, b, ^uint8(i))
+		}
+	}
+}
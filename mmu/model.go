@@ -0,0 +1,119 @@
+package mmu
+
+import "github.com/loizoskounios/game-boy-emulator/timer"
+
+// Model is the type for our Game Boy hardware model enumeration.
+type Model uint8
+
+// Enumerates the Game Boy hardware models.
+const (
+	ModelDMG Model = iota
+	ModelMGB
+	ModelSGB
+	ModelCGB
+)
+
+func (model Model) String() string {
+	switch model {
+	case ModelDMG:
+		return "DMG"
+	case ModelMGB:
+		return "MGB"
+	case ModelSGB:
+		return "SGB"
+	case ModelCGB:
+		return "CGB"
+	default:
+		return "?"
+	}
+}
+
+// dmgPostBootIO holds the documented values of the I/O registers after the DMG
+// boot ROM hands control over to the cartridge. Registers whose value is not
+// documented are left untouched.
+var dmgPostBootIO = map[uint16]uint8{
+	0xFF00: 0xCF, // P1
+	0xFF01: 0x00, // SB
+	0xFF02: 0x7E, // SC
+	0xFF04: 0xAB, // DIV
+	0xFF05: 0x00, // TIMA
+	0xFF06: 0x00, // TMA
+	0xFF07: 0xF8, // TAC
+	0xFF0F: 0xE1, // IF
+	0xFF10: 0x80, // NR10
+	0xFF11: 0xBF, // NR11
+	0xFF12: 0xF3, // NR12
+	0xFF13: 0xFF, // NR13
+	0xFF14: 0xBF, // NR14
+	0xFF16: 0x3F, // NR21
+	0xFF17: 0x00, // NR22
+	0xFF18: 0xFF, // NR23
+	0xFF19: 0xBF, // NR24
+	0xFF1A: 0x7F, // NR30
+	0xFF1B: 0xFF, // NR31
+	0xFF1C: 0x9F, // NR32
+	0xFF1D: 0xFF, // NR33
+	0xFF1E: 0xBF, // NR34
+	0xFF20: 0xFF, // NR41
+	0xFF21: 0x00, // NR42
+	0xFF22: 0x00, // NR43
+	0xFF23: 0xBF, // NR44
+	0xFF24: 0x77, // NR50
+	0xFF25: 0xF3, // NR51
+	0xFF26: 0xF1, // NR52
+	0xFF40: 0x91, // LCDC
+	0xFF41: 0x85, // STAT
+	0xFF42: 0x00, // SCY
+	0xFF43: 0x00, // SCX
+	0xFF44: 0x00, // LY
+	0xFF45: 0x00, // LYC
+	0xFF46: 0xFF, // DMA
+	0xFF47: 0xFC, // BGP
+	0xFF4A: 0x00, // WY
+	0xFF4B: 0x00, // WX
+	0xFFFF: 0x00, // IE
+}
+
+// Returns the documented values of the I/O registers after the boot ROM of the
+// provided model hands control over to the cartridge.
+func postBootIO(model Model) map[uint16]uint8 {
+	io := make(map[uint16]uint8, len(dmgPostBootIO))
+	for addr, b := range dmgPostBootIO {
+		io[addr] = b
+	}
+
+	switch model {
+	case ModelSGB:
+		io[0xFF26] = 0xF0
+		delete(io, 0xFF04)
+		delete(io, 0xFF41)
+		delete(io, 0xFF44)
+	case ModelCGB:
+		io[0xFF02] = 0x7F
+		io[0xFF46] = 0x00
+		delete(io, 0xFF04)
+		delete(io, 0xFF41)
+		delete(io, 0xFF44)
+	}
+
+	return io
+}
+
+// SkipBoot unmaps the boot ROM and sets the I/O registers to the values the
+// boot ROM of the provided model leaves them in.
+func (mmu *MemoryManagementUnit) SkipBoot(model Model) {
+	for addr, b := range postBootIO(model) {
+		switch addr {
+		case timer.AddressDIV:
+			mmu.timer.SetDIV(b)
+		case AddressDMA:
+			// The register holds the value without a transfer running.
+			mmu.m.Store(addr, b)
+		default:
+			mmu.Store(addr, b)
+		}
+	}
+
+	mmu.bootROMMapped = false
+	mmu.m.Store(BootROMDisable, 0x01)
+}
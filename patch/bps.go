@@ -0,0 +1,94 @@
+package patch
+
+// Actions making up the body of a BPS patch.
+const (
+	bpsSourceRead = iota
+	bpsTargetRead
+	bpsSourceCopy
+	bpsTargetCopy
+)
+
+// Applies the provided BPS patch. BPS patches hold the sizes of the source
+// and target images and some metadata, followed by actions that build the
+// target image from runs of the source image, of the patch itself, or of the
+// target image built so far.
+func applyBPS(rom, patch []uint8) ([]uint8, error) {
+	f, err := readFooter(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reader{b: patch[:len(patch)-footerSize], pos: len(magicBPS)}
+	sourceSize := r.number()
+	targetSize := r.number()
+	r.bytes(r.number())
+	if r.err != nil {
+		return nil, r.err
+	}
+	if sourceSize != len(rom) {
+		return nil, ErrSourceSize
+	}
+	if err := verify("source", rom, f.source); err != nil {
+		return nil, err
+	}
+
+	out := make([]uint8, targetSize)
+	var pos, sourceOffset, targetOffset int
+
+	for !r.done() {
+		action := r.number()
+		length := action>>2 + 1
+		if pos+length > len(out) {
+			return nil, ErrCorrupt
+		}
+
+		switch action & 0x03 {
+		case bpsSourceRead:
+			if pos+length > len(rom) {
+				return nil, ErrCorrupt
+			}
+			copy(out[pos:], rom[pos:pos+length])
+		case bpsTargetRead:
+			copy(out[pos:], r.bytes(length))
+		case bpsSourceCopy:
+			sourceOffset += r.offset()
+			if sourceOffset < 0 || sourceOffset+length > len(rom) {
+				return nil, ErrCorrupt
+			}
+			copy(out[pos:], rom[sourceOffset:sourceOffset+length])
+			sourceOffset += length
+		case bpsTargetCopy:
+			targetOffset += r.offset()
+			if targetOffset < 0 || targetOffset >= pos {
+				return nil, ErrCorrupt
+			}
+			// The run may overlap the bytes it produces, repeating them.
+			for i := 0; i < length; i++ {
+				out[pos+i] = out[targetOffset+i]
+			}
+			targetOffset += length
+		}
+
+		if r.err != nil {
+			return nil, r.err
+		}
+		pos += length
+	}
+
+	if err := verify("target", out, f.target); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Returns the next signed offset of the patch, encoded as a number holding
+// the sign in bit 0 and the magnitude in the remaining bits.
+func (r *reader) offset() int {
+	n := r.number()
+	if n&1 != 0 {
+		return -(n >> 1)
+	}
+
+	return n >> 1
+}
@@ -0,0 +1,52 @@
+package patch
+
+import "bytes"
+
+// Marker found at the end of the records of IPS patches.
+var eofIPS = []uint8("EOF")
+
+// Applies the provided IPS patch. IPS patches are a list of records, each
+// holding a 24-bit offset, a 16-bit length and the bytes to write there. A
+// zero length marks a run-length encoded record, holding a 16-bit length and
+// the byte to repeat instead. The list ends with "EOF", optionally followed by
+// the 24-bit size the image is truncated to.
+func applyIPS(rom, patch []uint8) ([]uint8, error) {
+	r := &reader{b: patch, pos: len(magicIPS)}
+	out := make([]uint8, len(rom))
+	copy(out, rom)
+
+	for {
+		if bytes.HasPrefix(patch[r.pos:], eofIPS) {
+			r.pos += len(eofIPS)
+			break
+		}
+
+		offset := r.bigEndian(3)
+		length := r.bigEndian(2)
+
+		var data []uint8
+		if length == 0 {
+			length = r.bigEndian(2)
+			data = bytes.Repeat([]uint8{r.byte()}, length)
+		} else {
+			data = r.bytes(length)
+		}
+
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		if end := offset + length; end > len(out) {
+			out = append(out, make([]uint8, end-len(out))...)
+		}
+		copy(out[offset:], data)
+	}
+
+	if !r.done() {
+		if size := r.bigEndian(3); r.err == nil && size < len(out) {
+			out = out[:size]
+		}
+	}
+
+	return out, nil
+}
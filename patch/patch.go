@@ -0,0 +1,211 @@
+// Package patch applies IPS, UPS and BPS patches to ROM images, as used to
+// distribute translations and ROM hacks without distributing the ROM itself.
+package patch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+var (
+	// ErrUnknownFormat is returned when a patch is not in any of the
+	// supported formats.
+	ErrUnknownFormat = errors.New("unknown patch format")
+	// ErrCorrupt is returned when a patch is truncated, or refers to data
+	// outside of the ROM images it applies to.
+	ErrCorrupt = errors.New("corrupt patch")
+	// ErrSourceSize is returned when a patch was made for a ROM image of a
+	// different size.
+	ErrSourceSize = errors.New("patch does not apply to a ROM image of this size")
+)
+
+// Format is the type for our patch format enumeration.
+type Format uint8
+
+// Enumerates the supported patch formats.
+const (
+	FormatUnknown Format = iota
+	FormatIPS
+	FormatUPS
+	FormatBPS
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatIPS:
+		return "IPS"
+	case FormatUPS:
+		return "UPS"
+	case FormatBPS:
+		return "BPS"
+	default:
+		return "unknown"
+	}
+}
+
+// Magic numbers found at the start of patches of each format.
+var (
+	magicIPS = []uint8("PATCH")
+	magicUPS = []uint8("UPS1")
+	magicBPS = []uint8("BPS1")
+)
+
+// Size of the footer of UPS and BPS patches, holding the CRC-32 checksums of
+// the source image, the target image and the patch itself.
+const footerSize = 12
+
+// ChecksumError is returned when the CRC-32 checksum of the source image, the
+// patched image or the patch itself does not match the one stored in a UPS or
+// BPS patch.
+type ChecksumError struct {
+	// Of is what the checksum was computed over: "source", "target" or
+	// "patch".
+	Of       string
+	Stored   uint32
+	Computed uint32
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: stored 0x%08X, computed 0x%08X", e.Of, e.Stored, e.Computed)
+}
+
+// Detect returns the format of the provided patch.
+func Detect(patch []uint8) Format {
+	switch {
+	case bytes.HasPrefix(patch, magicIPS):
+		return FormatIPS
+	case bytes.HasPrefix(patch, magicUPS):
+		return FormatUPS
+	case bytes.HasPrefix(patch, magicBPS):
+		return FormatBPS
+	default:
+		return FormatUnknown
+	}
+}
+
+// Apply returns a copy of the provided ROM image with the provided patch
+// applied. The format of the patch is detected from its contents. The ROM
+// image itself is left untouched.
+func Apply(rom, patch []uint8) ([]uint8, error) {
+	switch Detect(patch) {
+	case FormatIPS:
+		return applyIPS(rom, patch)
+	case FormatUPS:
+		return applyUPS(rom, patch)
+	case FormatBPS:
+		return applyBPS(rom, patch)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// Checksums of a UPS or BPS patch, stored in its footer.
+type footer struct {
+	source, target, patch uint32
+}
+
+// Returns the footer of the provided UPS or BPS patch, after verifying the
+// checksum of the patch itself.
+func readFooter(patch []uint8) (footer, error) {
+	if len(patch) < footerSize {
+		return footer{}, ErrCorrupt
+	}
+
+	body := len(patch) - footerSize
+	f := footer{
+		source: binary.LittleEndian.Uint32(patch[body:]),
+		target: binary.LittleEndian.Uint32(patch[body+4:]),
+		patch:  binary.LittleEndian.Uint32(patch[body+8:]),
+	}
+
+	if computed := crc32.ChecksumIEEE(patch[:body+8]); computed != f.patch {
+		return footer{}, &ChecksumError{Of: "patch", Stored: f.patch, Computed: computed}
+	}
+
+	return f, nil
+}
+
+// Verifies the checksum of the provided image against the one stored in the
+// footer of a UPS or BPS patch.
+func verify(of string, image []uint8, stored uint32) error {
+	if computed := crc32.ChecksumIEEE(image); computed != stored {
+		return &ChecksumError{Of: of, Stored: stored, Computed: computed}
+	}
+
+	return nil
+}
+
+// reader reads the body of a patch, recording the first error it runs into so
+// that callers only need to check for it once per record.
+type reader struct {
+	b   []uint8
+	pos int
+	err error
+}
+
+// Returns the next n bytes of the patch, or nil if there are not as many left.
+func (r *reader) bytes(n int) []uint8 {
+	if r.err != nil || n < 0 || n > len(r.b)-r.pos {
+		r.err = ErrCorrupt
+		return nil
+	}
+
+	b := r.b[r.pos : r.pos+n]
+	r.pos += n
+
+	return b
+}
+
+// Returns the next byte of the patch.
+func (r *reader) byte() uint8 {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+
+	return 0
+}
+
+// Returns the next big-endian number of the provided width in bytes.
+func (r *reader) bigEndian(width int) int {
+	var n int
+	for _, b := range r.bytes(width) {
+		n = n<<8 | int(b)
+	}
+
+	return n
+}
+
+// Returns whether the whole patch has been read.
+func (r *reader) done() bool {
+	return r.pos >= len(r.b)
+}
+
+// Returns the next variable-length number of the patch, as encoded by UPS and
+// BPS: 7 bits per byte, least significant first, with bit 7 set on the last
+// byte, and each continuation adding one to the remaining value so that every
+// number has a single encoding.
+func (r *reader) number() int {
+	var n, shift uint64 = 0, 1
+	for r.err == nil {
+		b := r.byte()
+		n += uint64(b&0x7F) * shift
+		if b&0x80 != 0 {
+			break
+		}
+		shift <<= 7
+		n += shift
+
+		if shift > 1<<56 {
+			r.err = ErrCorrupt
+		}
+	}
+
+	if n > 1<<31 {
+		r.err = ErrCorrupt
+	}
+
+	return int(n)
+}
@@ -0,0 +1,180 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+)
+
+// Returns the UPS and BPS encoding of the provided number.
+func number(n int) []uint8 {
+	var b []uint8
+	for {
+		x := uint8(n & 0x7F)
+		n >>= 7
+		if n == 0 {
+			return append(b, x|0x80)
+		}
+		b = append(b, x)
+		n--
+	}
+}
+
+// Returns the provided UPS or BPS patch body followed by its footer.
+func withFooter(body, source, target []uint8) []uint8 {
+	p := append([]uint8{}, body...)
+	p = binary.LittleEndian.AppendUint32(p, crc32.ChecksumIEEE(source))
+	p = binary.LittleEndian.AppendUint32(p, crc32.ChecksumIEEE(target))
+
+	return binary.LittleEndian.AppendUint32(p, crc32.ChecksumIEEE(p))
+}
+
+func join(parts ...[]uint8) []uint8 {
+	return bytes.Join(parts, nil)
+}
+
+func TestDetect(t *testing.T) {
+	var testCases = []struct {
+		patch    string
+		expected Format
+	}{
+		{"PATCHEOF", FormatIPS},
+		{"UPS1", FormatUPS},
+		{"BPS1", FormatBPS},
+		{"PAT", FormatUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.patch, func(t *testing.T) {
+			if f := Detect([]uint8(tc.patch)); f != tc.expected {
+				t.Errorf("got %s, expected %s", f, tc.expected)
+			}
+		})
+	}
+
+	if _, err := Apply(nil, []uint8("ZIP")); err != ErrUnknownFormat {
+		t.Errorf("got %v, expected %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestIPS(t *testing.T) {
+	rom := []uint8{0, 1, 2, 3, 4, 5, 6, 7}
+	original := append([]uint8{}, rom...)
+
+	var testCases = []struct {
+		name     string
+		patch    []uint8
+		expected []uint8
+	}{
+		{
+			"record",
+			join(magicIPS, []uint8{0, 0, 2, 0, 2, 0xAA, 0xBB}, eofIPS),
+			[]uint8{0, 1, 0xAA, 0xBB, 4, 5, 6, 7},
+		},
+		{
+			"rle",
+			join(magicIPS, []uint8{0, 0, 1, 0, 0, 0, 3, 0xCC}, eofIPS),
+			[]uint8{0, 0xCC, 0xCC, 0xCC, 4, 5, 6, 7},
+		},
+		{
+			"extend",
+			join(magicIPS, []uint8{0, 0, 9, 0, 1, 0xDD}, eofIPS),
+			[]uint8{0, 1, 2, 3, 4, 5, 6, 7, 0, 0xDD},
+		},
+		{
+			"truncate",
+			join(magicIPS, eofIPS, []uint8{0, 0, 4}),
+			[]uint8{0, 1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Apply(rom, tc.patch)
+			if err != nil {
+				t.Fatalf("got %v, expected nil", err)
+			}
+			if !bytes.Equal(out, tc.expected) {
+				t.Errorf("got %v, expected %v", out, tc.expected)
+			}
+		})
+	}
+
+	if !bytes.Equal(rom, original) {
+		t.Errorf("got ROM %v, expected it untouched", rom)
+	}
+
+	if _, err := Apply(rom, join(magicIPS, []uint8{0, 0, 2, 0, 4, 0xAA})); err != ErrCorrupt {
+		t.Errorf("truncated: got %v, expected %v", err, ErrCorrupt)
+	}
+}
+
+func TestUPS(t *testing.T) {
+	rom := []uint8{0, 1, 2, 3, 4, 5, 6, 7}
+	target := []uint8{0, 1, 0xF2, 3, 4, 0xA5, 0xB6, 7, 8}
+
+	// Skip 2 bytes and XOR 1, then skip 1 more after the terminator, XOR 2
+	// and write the byte past the end of the source.
+	body := join(magicUPS, number(len(rom)), number(len(target)),
+		number(2), []uint8{0xF0, 0x00},
+		number(1), []uint8{0xA0, 0xB0}, []uint8{0x00},
+		number(0), []uint8{0x08, 0x00})
+	patch := withFooter(body, rom, target)
+
+	out, err := Apply(rom, patch)
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+	if !bytes.Equal(out, target) {
+		t.Errorf("got %v, expected %v", out, target)
+	}
+
+	other := []uint8{7, 6, 5, 4, 3, 2, 1, 0}
+	var checksumErr *ChecksumError
+	if _, err := Apply(other, patch); !errors.As(err, &checksumErr) || checksumErr.Of != "source" {
+		t.Errorf("got %v, expected source checksum mismatch", err)
+	}
+
+	if _, err := Apply(rom[:4], patch); err != ErrSourceSize {
+		t.Errorf("got %v, expected %v", err, ErrSourceSize)
+	}
+
+	patch[len(magicUPS)+4] ^= 0xFF
+	if _, err := Apply(rom, patch); !errors.As(err, &checksumErr) || checksumErr.Of != "patch" {
+		t.Errorf("got %v, expected patch checksum mismatch", err)
+	}
+}
+
+func TestBPS(t *testing.T) {
+	rom := []uint8{'A', 'B', 'C', 'D', 'E', 'F'}
+	target := []uint8{'A', 'B', 'x', 'y', 'E', 'F', 'C', 'D', 'D', 'D', 'D'}
+
+	action := func(kind, length int) []uint8 {
+		return number((length-1)<<2 | kind)
+	}
+	body := join(magicBPS, number(len(rom)), number(len(target)), number(4), []uint8("meta"),
+		action(bpsSourceRead, 2),
+		action(bpsTargetRead, 2), []uint8("xy"),
+		action(bpsSourceRead, 2),
+		// Copy "CD" from source offset 2.
+		action(bpsSourceCopy, 2), number(2<<1),
+		// Repeat the "D" at target offset 7.
+		action(bpsTargetCopy, 3), number(7<<1))
+	patch := withFooter(body, rom, target)
+
+	out, err := Apply(rom, patch)
+	if err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+	if !bytes.Equal(out, target) {
+		t.Errorf("got %q, expected %q", out, target)
+	}
+
+	var checksumErr *ChecksumError
+	wrong := withFooter(body, rom, []uint8("other"))
+	if _, err := Apply(rom, wrong); !errors.As(err, &checksumErr) || checksumErr.Of != "target" {
+		t.Errorf("got %v, expected target checksum mismatch", err)
+	}
+}
@@ -0,0 +1,50 @@
+package patch
+
+// Applies the provided UPS patch. UPS patches hold the sizes of the source
+// and target images, followed by hunks that each skip a number of bytes and
+// XOR the bytes that follow with the image, up to and including a zero byte.
+func applyUPS(rom, patch []uint8) ([]uint8, error) {
+	f, err := readFooter(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reader{b: patch[:len(patch)-footerSize], pos: len(magicUPS)}
+	sourceSize := r.number()
+	targetSize := r.number()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if sourceSize != len(rom) {
+		return nil, ErrSourceSize
+	}
+	if err := verify("source", rom, f.source); err != nil {
+		return nil, err
+	}
+
+	out := make([]uint8, targetSize)
+	copy(out, rom)
+
+	for pos := 0; !r.done(); pos++ {
+		pos += r.number()
+		for ; ; pos++ {
+			b := r.byte()
+			if r.err != nil {
+				return nil, r.err
+			}
+			if b == 0 {
+				break
+			}
+			if pos >= len(out) {
+				return nil, ErrCorrupt
+			}
+			out[pos] ^= b
+		}
+	}
+
+	if err := verify("target", out, f.target); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
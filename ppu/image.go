@@ -0,0 +1,24 @@
+package ppu
+
+import (
+	"image"
+	"image/color"
+)
+
+// Palette maps the shades of a frame to the colors they are shown in.
+var Palette = color.Palette{
+	color.Gray{Y: 0xFF},
+	color.Gray{Y: 0xAA},
+	color.Gray{Y: 0x55},
+	color.Gray{Y: 0x00},
+}
+
+// Image returns a copy of the frame as an image using Palette.
+func (f *Frame) Image() *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, Width, Height), Palette)
+	for y := range f {
+		copy(img.Pix[y*img.Stride:], f[y][:])
+	}
+
+	return img
+}
@@ -0,0 +1,507 @@
+// Package ppu implements the Game Boy pixel processing unit: video RAM, OAM,
+// the LCD registers, and the rendering of frames out of them.
+package ppu
+
+import (
+	"sort"
+
+	"github.com/loizoskounios/game-boy-emulator/interrupts"
+)
+
+// Addresses of the LCD registers.
+const (
+	AddressLCDC uint16 = 0xFF40
+	AddressSTAT uint16 = 0xFF41
+	AddressSCY  uint16 = 0xFF42
+	AddressSCX  uint16 = 0xFF43
+	AddressLY   uint16 = 0xFF44
+	AddressLYC  uint16 = 0xFF45
+	AddressBGP  uint16 = 0xFF47
+	AddressOBP0 uint16 = 0xFF48
+	AddressOBP1 uint16 = 0xFF49
+	AddressWY   uint16 = 0xFF4A
+	AddressWX   uint16 = 0xFF4B
+)
+
+// Video RAM, as mapped in memory.
+const (
+	VRAMStart uint16 = 0x8000
+	VRAMEnd   uint16 = 0x9FFF
+)
+
+// Object attribute memory, as mapped in memory. It holds 40 sprites of 4
+// bytes each: Y+16, X+8, tile index and attributes.
+const (
+	OAMStart uint16 = 0xFE00
+	OAMEnd   uint16 = 0xFE9F
+)
+
+// Bits of LCDC.
+const (
+	lcdcBGEnable     uint8 = 1 << 0
+	lcdcOBJEnable    uint8 = 1 << 1
+	lcdcOBJSize      uint8 = 1 << 2
+	lcdcTileMap      uint8 = 1 << 3
+	lcdcTileData     uint8 = 1 << 4
+	lcdcWindowEnable uint8 = 1 << 5
+	lcdcWindowMap    uint8 = 1 << 6
+	lcdcLCDEnable    uint8 = 1 << 7
+)
+
+// Bits of STAT. The lower 2 bits hold the current mode, and bit 2 is set
+// while LY equals LYC. Bits 3 to 6 select the conditions that raise the STAT
+// interrupt line.
+const (
+	statCoincidence uint8 = 1 << 2
+	statHBlank      uint8 = 1 << 3
+	statVBlank      uint8 = 1 << 4
+	statOAMScan     uint8 = 1 << 5
+	statLYC         uint8 = 1 << 6
+	statSources     uint8 = statHBlank | statVBlank | statOAMScan | statLYC
+)
+
+// Modes of the PPU, as reported in STAT.
+const (
+	modeHBlank uint8 = iota
+	modeVBlank
+	modeOAMScan
+	modeDrawing
+)
+
+// Bits of the attributes of a sprite.
+const (
+	attrPalette  uint8 = 1 << 4
+	attrXFlip    uint8 = 1 << 5
+	attrYFlip    uint8 = 1 << 6
+	attrPriority uint8 = 1 << 7
+)
+
+// spritesPerLine is the number of sprites drawn on a line at most. Sprites
+// past it in OAM order are left out, even where the ones drawn are off-screen.
+const spritesPerLine = 10
+
+// Offsets into video RAM of the two tile maps, 32×32 tile indices each, and
+// of the tile data addressed with signed indices.
+const (
+	tileMap0       uint16 = 0x1800
+	tileMap1       uint16 = 0x1C00
+	tileDataSigned uint16 = 0x1000
+)
+
+// Timing of the LCD, in machine cycles. Every line takes 114 machine cycles:
+// 20 scanning OAM, 43 drawing pixels, and the rest in horizontal blanking.
+// 144 visible lines are followed by 10 lines of vertical blanking.
+const (
+	cyclesPerLine = 114
+	oamScanCycles = 20
+	drawingCycles = 43
+	lines         = 154
+)
+
+// The window is drawn from WX-7 onwards, so it is off-screen past this WX.
+const windowMaxX = Width + 6
+
+// Width and Height are the dimensions of the LCD, in pixels.
+const (
+	Width  = 160
+	Height = 144
+)
+
+// Frame holds the shade of every pixel of the LCD, from 0 for white to 3 for
+// black.
+type Frame [Height][Width]uint8
+
+// PPU is the Game Boy pixel processing unit. While the LCD is on, it draws
+// one line of the frame every 114 machine cycles, out of the tile data and
+// tile maps in video RAM and the LCD registers as they are when the line
+// starts being drawn.
+type PPU struct {
+	vram [VRAMEnd - VRAMStart + 1]uint8
+	oam  [OAMEnd - OAMStart + 1]uint8
+
+	lcdc uint8
+	stat uint8
+	scy  uint8
+	scx  uint8
+	ly   uint8
+	lyc  uint8
+	bgp  uint8
+	obp0 uint8
+	obp1 uint8
+	wy   uint8
+	wx   uint8
+
+	// cycles is the number of machine cycles spent on the current line.
+	cycles int
+	mode   uint8
+
+	// statLine is the state of the STAT interrupt line, which ORs together
+	// the conditions selected in STAT. The interrupt is only requested when
+	// it goes high, so a condition that becomes true while another one holds
+	// it high is blocked.
+	statLine bool
+
+	// windowY is set once LY has matched WY during the current frame, after
+	// which the window is drawn on every line it is enabled on. windowLine is
+	// the line of the window drawn next, which only advances on lines the
+	// window was drawn on.
+	windowY    bool
+	windowLine uint8
+
+	// The frame being drawn, and the last one completed.
+	back, front *Frame
+
+	interrupts *interrupts.Controller
+}
+
+// New returns a pointer to a new PPU that requests its interrupts through the
+// provided interrupt controller.
+func New(ic *interrupts.Controller) *PPU {
+	return &PPU{back: &Frame{}, front: &Frame{}, interrupts: ic}
+}
+
+// Reset clears video RAM and the LCD registers, turning the LCD off.
+func (p *PPU) Reset() {
+	*p = PPU{back: &Frame{}, front: &Frame{}, interrupts: p.interrupts}
+}
+
+// Frame returns the last frame completed, which stays valid until the next one
+// is.
+func (p *PPU) Frame() *Frame {
+	return p.front
+}
+
+// Tick advances the PPU by one machine cycle. Entering line 144 requests the
+// VBlank interrupt.
+func (p *PPU) Tick() {
+	if p.lcdc&lcdcLCDEnable == 0 {
+		return
+	}
+
+	p.cycles++
+	if p.cycles == cyclesPerLine {
+		p.cycles = 0
+		p.ly++
+		switch p.ly {
+		case Height:
+			p.back, p.front = p.front, p.back
+			p.interrupts.Request(interrupts.VBlank)
+		case lines:
+			p.ly = 0
+			p.windowY = false
+			p.windowLine = 0
+		}
+	}
+
+	switch {
+	case p.ly >= Height:
+		p.mode = modeVBlank
+	case p.cycles < oamScanCycles:
+		p.mode = modeOAMScan
+	case p.cycles < oamScanCycles+drawingCycles:
+		if p.mode != modeDrawing {
+			p.renderLine()
+		}
+		p.mode = modeDrawing
+	default:
+		p.mode = modeHBlank
+	}
+
+	p.updateSTAT()
+}
+
+// Updates the STAT interrupt line, requesting the interrupt if it goes high.
+// The line stays low while the LCD is off.
+func (p *PPU) updateSTAT() {
+	line := p.lcdc&lcdcLCDEnable != 0 && p.statConditions()&p.stat != 0
+	if line && !p.statLine {
+		p.interrupts.Request(interrupts.LCDStat)
+	}
+	p.statLine = line
+}
+
+// Returns the bits of STAT selecting the conditions that currently hold.
+func (p *PPU) statConditions() uint8 {
+	var conditions uint8
+	switch p.mode {
+	case modeHBlank:
+		conditions |= statHBlank
+	case modeVBlank:
+		conditions |= statVBlank
+	case modeOAMScan:
+		conditions |= statOAMScan
+	}
+	if p.ly == p.lyc {
+		conditions |= statLYC
+	}
+
+	return conditions
+}
+
+// Turns the LCD off, which blanks it and stops the PPU at the start of line 0.
+func (p *PPU) disable() {
+	p.ly = 0
+	p.cycles = 0
+	p.mode = modeHBlank
+	p.windowY = false
+	p.windowLine = 0
+	*p.front = Frame{}
+}
+
+// Draws the current line into the back frame: the background, with the
+// window over it from WX-7 onwards, and the sprites over both. WX below 7
+// shifts the window left, hiding its first 7-WX pixels. Clearing bit 0 of
+// LCDC hides the background and the window, and bit 1 the sprites.
+func (p *PPU) renderLine() {
+	if p.ly == p.wy {
+		p.windowY = true
+	}
+
+	var colors [Width]uint8
+	line := &p.back[p.ly]
+	if p.lcdc&lcdcBGEnable != 0 {
+		p.renderBackground(&colors)
+		for x, color := range colors {
+			line[x] = shade(p.bgp, color)
+		}
+	} else {
+		*line = [Width]uint8{}
+	}
+
+	if p.lcdc&lcdcOBJEnable != 0 {
+		p.renderSprites(line, &colors)
+	}
+}
+
+// Fills the provided line with the color numbers of the background and the
+// window.
+func (p *PPU) renderBackground(colors *[Width]uint8) {
+	y := p.ly + p.scy
+	for x := range colors {
+		colors[x] = p.mapPixel(p.lcdc&lcdcTileMap, uint8(x)+p.scx, y)
+	}
+
+	if p.lcdc&lcdcWindowEnable == 0 || !p.windowY || p.wx > windowMaxX {
+		return
+	}
+
+	start := int(p.wx) - 7
+	x := 0
+	if start > 0 {
+		x = start
+	}
+	for ; x < Width; x++ {
+		colors[x] = p.mapPixel(p.lcdc&lcdcWindowMap, uint8(x-start), p.windowLine)
+	}
+	p.windowLine++
+}
+
+// sprite is an entry of OAM.
+type sprite struct {
+	y, x  uint8
+	tile  uint8
+	attrs uint8
+}
+
+// Returns the height of sprites in pixels, 16 when bit 2 of LCDC is set and 8
+// otherwise.
+func (p *PPU) spriteHeight() uint8 {
+	if p.lcdc&lcdcOBJSize != 0 {
+		return 16
+	}
+
+	return 8
+}
+
+// Returns the sprites on the current line, at most spritesPerLine of them, in
+// OAM order.
+func (p *PPU) scanOAM() []sprite {
+	sprites := make([]sprite, 0, spritesPerLine)
+	height := p.spriteHeight()
+	for i := 0; i < len(p.oam) && len(sprites) < spritesPerLine; i += 4 {
+		s := sprite{y: p.oam[i], x: p.oam[i+1], tile: p.oam[i+2], attrs: p.oam[i+3]}
+		if row := p.ly + 16 - s.y; row < height {
+			sprites = append(sprites, s)
+		}
+	}
+
+	return sprites
+}
+
+// Draws the sprites on the current line over the provided line, whose
+// background and window color numbers are provided. Where sprites overlap,
+// the one with the smaller X is drawn, and the one earlier in OAM for equal
+// X. A sprite with its priority bit set is hidden behind background and
+// window colors 1 to 3.
+func (p *PPU) renderSprites(line, colors *[Width]uint8) {
+	sprites := p.scanOAM()
+	sort.SliceStable(sprites, func(i, j int) bool {
+		return sprites[i].x < sprites[j].x
+	})
+
+	height := p.spriteHeight()
+	for x := range line {
+		for _, s := range sprites {
+			col := uint8(x) + 8 - s.x
+			if col >= 8 {
+				continue
+			}
+
+			color := p.spritePixel(s, height, col)
+			if color == 0 {
+				continue
+			}
+
+			if s.attrs&attrPriority == 0 || colors[x] == 0 {
+				palette := p.obp0
+				if s.attrs&attrPalette != 0 {
+					palette = p.obp1
+				}
+				line[x] = shade(palette, color)
+			}
+			break
+		}
+	}
+}
+
+// Returns the color number of the pixel of the provided sprite at the
+// provided column on the current line. Sprites always use the tile data at
+// 0x8000, and 8×16 ones the pair of tiles starting at an even index.
+func (p *PPU) spritePixel(s sprite, height, col uint8) uint8 {
+	row := p.ly + 16 - s.y
+	if s.attrs&attrYFlip != 0 {
+		row = height - 1 - row
+	}
+	if s.attrs&attrXFlip != 0 {
+		col = 7 - col
+	}
+
+	tile := s.tile
+	if height == 16 {
+		tile &^= 1
+	}
+
+	return p.tilePixel(uint16(tile)*16, col, row)
+}
+
+// Returns the color number of the pixel at the provided coordinates of the
+// tile map selected by the provided LCDC bit, the second one when it is set.
+func (p *PPU) mapPixel(selected uint8, x, y uint8) uint8 {
+	tileMap := tileMap0
+	if selected != 0 {
+		tileMap = tileMap1
+	}
+
+	tile := p.vram[tileMap+uint16(y/8)*32+uint16(x/8)]
+	return p.tilePixel(p.tileAddress(tile), x%8, y%8)
+}
+
+// Returns the offset into video RAM of the background tile with the provided
+// index. The index is unsigned and relative to 0x8000 when bit 4 of LCDC is
+// set, and signed and relative to 0x9000 otherwise.
+func (p *PPU) tileAddress(index uint8) uint16 {
+	if p.lcdc&lcdcTileData != 0 {
+		return uint16(index) * 16
+	}
+
+	return tileDataSigned + uint16(int16(int8(index))*16)
+}
+
+// Returns the color number, from 0 to 3, of the pixel at the provided
+// coordinates of the tile at the provided offset. Each row of a tile is 2
+// bytes, the first holding the lower bit of every pixel and the second the
+// upper one, leftmost pixel first.
+func (p *PPU) tilePixel(tile uint16, x, y uint8) uint8 {
+	lo := p.vram[tile+uint16(y)*2]
+	hi := p.vram[tile+uint16(y)*2+1]
+	bit := 7 - x
+
+	return (hi>>bit&1)<<1 | lo>>bit&1
+}
+
+// Returns the shade the provided palette maps the provided color number to.
+func shade(palette, color uint8) uint8 {
+	return palette >> (color * 2) & 0x03
+}
+
+// Load returns the contents of video RAM, OAM or an LCD register. STAT
+// reports the current mode, and whether LY equals LYC.
+func (p *PPU) Load(addr uint16) uint8 {
+	switch addr {
+	case AddressLCDC:
+		return p.lcdc
+	case AddressSTAT:
+		stat := 0x80 | p.stat | p.mode
+		if p.ly == p.lyc {
+			stat |= statCoincidence
+		}
+		return stat
+	case AddressSCY:
+		return p.scy
+	case AddressSCX:
+		return p.scx
+	case AddressLY:
+		return p.ly
+	case AddressLYC:
+		return p.lyc
+	case AddressBGP:
+		return p.bgp
+	case AddressOBP0:
+		return p.obp0
+	case AddressOBP1:
+		return p.obp1
+	case AddressWY:
+		return p.wy
+	case AddressWX:
+		return p.wx
+	}
+
+	switch {
+	case addr >= VRAMStart && addr <= VRAMEnd:
+		return p.vram[addr-VRAMStart]
+	case addr >= OAMStart && addr <= OAMEnd:
+		return p.oam[addr-OAMStart]
+	}
+
+	return 0xFF
+}
+
+// Store writes video RAM, OAM or an LCD register. LY and the lower 3 bits of
+// STAT are read-only. Clearing bit 7 of LCDC turns the LCD off.
+func (p *PPU) Store(addr uint16, b uint8) {
+	switch addr {
+	case AddressLCDC:
+		if p.lcdc&^b&lcdcLCDEnable != 0 {
+			p.disable()
+		}
+		p.lcdc = b
+		p.updateSTAT()
+	case AddressSTAT:
+		p.stat = b & statSources
+		p.updateSTAT()
+	case AddressSCY:
+		p.scy = b
+	case AddressSCX:
+		p.scx = b
+	case AddressLYC:
+		p.lyc = b
+		p.updateSTAT()
+	case AddressBGP:
+		p.bgp = b
+	case AddressOBP0:
+		p.obp0 = b
+	case AddressOBP1:
+		p.obp1 = b
+	case AddressWY:
+		p.wy = b
+	case AddressWX:
+		p.wx = b
+	}
+
+	switch {
+	case addr >= VRAMStart && addr <= VRAMEnd:
+		p.vram[addr-VRAMStart] = b
+	case addr >= OAMStart && addr <= OAMEnd:
+		p.oam[addr-OAMStart] = b
+	}
+}
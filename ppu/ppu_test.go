@@ -0,0 +1,527 @@
+package ppu
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/loizoskounios/game-boy-emulator/interrupts"
+)
+
+// Runs the provided PPU for a whole frame, from the start of line 0.
+func runFrame(p *PPU) {
+	for i := 0; i < cyclesPerLine*lines; i++ {
+		p.Tick()
+	}
+}
+
+// Stores the provided tile at the provided address, with every row of it set
+// to the provided pair of bytes.
+func storeTile(p *PPU, addr uint16, lo, hi uint8) {
+	for row := uint16(0); row < 8; row++ {
+		p.Store(addr+row*2, lo)
+		p.Store(addr+row*2+1, hi)
+	}
+}
+
+func TestBackground(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		lcdc     uint8
+		scx, scy uint8
+		bgp      uint8
+		setup    func(p *PPU)
+		x, y     int
+		expected uint8
+	}{
+		{"unsigned tile data", 0x91, 0, 0, 0xE4, func(p *PPU) {
+			storeTile(p, 0x8010, 0xFF, 0xFF)
+			p.Store(0x9800, 0x01)
+		}, 7, 7, 3},
+		{"next tile", 0x91, 0, 0, 0xE4, func(p *PPU) {
+			storeTile(p, 0x8010, 0xFF, 0xFF)
+			p.Store(0x9800, 0x01)
+		}, 8, 0, 0},
+		{"signed tile data", 0x81, 0, 0, 0xE4, func(p *PPU) {
+			storeTile(p, 0x8800, 0x00, 0xFF)
+			storeTile(p, 0x8000, 0xFF, 0xFF)
+			p.Store(0x9800, 0x80)
+		}, 0, 0, 2},
+		{"signed tile data at 0x9000", 0x81, 0, 0, 0xE4, func(p *PPU) {
+			storeTile(p, 0x9000, 0xFF, 0x00)
+		}, 0, 0, 1},
+		{"bit order", 0x91, 0, 0, 0xE4, func(p *PPU) {
+			storeTile(p, 0x8000, 0x80, 0x01)
+		}, 7, 0, 2},
+		{"tile map 1", 0x99, 0, 0, 0xE4, func(p *PPU) {
+			storeTile(p, 0x8010, 0xFF, 0xFF)
+			p.Store(0x9800, 0x01)
+		}, 0, 0, 0},
+		{"tile map 1 selected", 0x99, 0, 0, 0xE4, func(p *PPU) {
+			storeTile(p, 0x8010, 0xFF, 0xFF)
+			p.Store(0x9C00, 0x01)
+		}, 0, 0, 3},
+		{"SCX", 0x91, 4, 0, 0xE4, func(p *PPU) {
+			storeTile(p, 0x8010, 0xFF, 0xFF)
+			p.Store(0x9801, 0x01)
+		}, 4, 0, 3},
+		{"SCX wraps around", 0x91, 0xFC, 0, 0xE4, func(p *PPU) {
+			storeTile(p, 0x8010, 0xFF, 0xFF)
+			p.Store(0x981F, 0x01)
+		}, 3, 0, 3},
+		{"SCY wraps around", 0x91, 0, 0xFC, 0xE4, func(p *PPU) {
+			storeTile(p, 0x8010, 0xFF, 0xFF)
+			p.Store(0x9800+31*32, 0x01)
+		}, 0, 3, 3},
+		{"BGP", 0x91, 0, 0, 0x1B, func(p *PPU) {
+			storeTile(p, 0x8010, 0xFF, 0xFF)
+			p.Store(0x9800, 0x01)
+		}, 0, 0, 0},
+		{"BGP color 0", 0x91, 0, 0, 0x1B, func(p *PPU) {}, 0, 0, 3},
+		{"background disabled", 0x90, 0, 0, 0x1B, func(p *PPU) {}, 0, 0, 0},
+		{"last line", 0x91, 0, 0, 0xE4, func(p *PPU) {
+			storeTile(p, 0x8010, 0xFF, 0xFF)
+			p.Store(0x9800+17*32+19, 0x01)
+		}, Width - 1, Height - 1, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s LCDC=0x%02X", tc.name, tc.lcdc), func(t *testing.T) {
+			p := New(interrupts.New())
+			tc.setup(p)
+			p.Store(AddressSCX, tc.scx)
+			p.Store(AddressSCY, tc.scy)
+			p.Store(AddressBGP, tc.bgp)
+			p.Store(AddressLCDC, tc.lcdc)
+			runFrame(p)
+
+			if s := p.Frame()[tc.y][tc.x]; s != tc.expected {
+				t.Errorf("got %d, expected %d", s, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		lcdc     uint8
+		wx, wy   uint8
+		x, y     int
+		expected uint8
+	}{
+		{"WX=7", 0xF1, 7, 0, 0, 0, 1},
+		{"WX=7 second half", 0xF1, 7, 0, 4, 0, 3},
+		{"left of the window", 0xF1, 17, 0, 9, 0, 0},
+		{"WX", 0xF1, 17, 0, 10, 0, 1},
+		{"window disabled", 0xD1, 7, 0, 0, 0, 0},
+		{"background disabled", 0xF0, 7, 0, 0, 0, 0},
+		{"window map 0", 0xB1, 7, 0, 0, 0, 0},
+		{"above the window", 0xF1, 7, 10, 0, 9, 0},
+		{"WY", 0xF1, 7, 10, 0, 10, 1},
+		{"WY off-screen", 0xF1, 7, 144, 0, 143, 0},
+		{"WX<7", 0xF1, 3, 0, 0, 0, 3},
+		{"WX=166", 0xF1, 166, 0, 159, 0, 1},
+		{"WX=166 left of the window", 0xF1, 166, 0, 158, 0, 0},
+		{"WX past the screen", 0xF1, 167, 0, 159, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s LCDC=0x%02X WX=%d WY=%d", tc.name, tc.lcdc, tc.wx, tc.wy), func(t *testing.T) {
+			p := New(interrupts.New())
+			// The first window tile is black, with its leftmost 4 pixels
+			// light gray, and the others are black.
+			storeTile(p, 0x8010, 0xFF, 0x0F)
+			storeTile(p, 0x8020, 0xFF, 0xFF)
+			p.Store(0x9C00, 0x01)
+			for i := uint16(1); i < 32; i++ {
+				p.Store(0x9C00+i, 0x02)
+			}
+			p.Store(AddressBGP, 0xE4)
+			p.Store(AddressWX, tc.wx)
+			p.Store(AddressWY, tc.wy)
+			p.Store(AddressLCDC, tc.lcdc)
+			runFrame(p)
+
+			if s := p.Frame()[tc.y][tc.x]; s != tc.expected {
+				t.Errorf("got %d, expected %d", s, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWindowLineCounter(t *testing.T) {
+	p := New(interrupts.New())
+	// Only the second row of window tiles is black.
+	storeTile(p, 0x8010, 0xFF, 0xFF)
+	p.Store(0x9C20, 0x01)
+	p.Store(AddressBGP, 0xE4)
+	p.Store(AddressWX, 7)
+	p.Store(AddressLCDC, 0xF1)
+
+	// The window is hidden on lines 5 to 9, so the lines after them draw it
+	// from where it was left.
+	for ly := 0; ly < lines; ly++ {
+		switch ly {
+		case 5:
+			p.Store(AddressLCDC, 0xD1)
+		case 10:
+			p.Store(AddressLCDC, 0xF1)
+		}
+		for i := 0; i < cyclesPerLine; i++ {
+			p.Tick()
+		}
+	}
+
+	var testCases = []struct {
+		y        int
+		expected uint8
+	}{
+		{4, 0},
+		{12, 0},
+		{13, 3},
+		{20, 3},
+		{21, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("LY=%d", tc.y), func(t *testing.T) {
+			if s := p.Frame()[tc.y][0]; s != tc.expected {
+				t.Errorf("got %d, expected %d", s, tc.expected)
+			}
+		})
+	}
+}
+
+// Stores the provided sprite at the provided index of OAM.
+func storeSprite(p *PPU, index int, y, x, tile, attrs uint8) {
+	addr := OAMStart + uint16(index)*4
+	p.Store(addr, y)
+	p.Store(addr+1, x)
+	p.Store(addr+2, tile)
+	p.Store(addr+3, attrs)
+}
+
+func TestSprites(t *testing.T) {
+	// Sets every pixel of the background to color 1.
+	background := func(p *PPU) {
+		storeTile(p, 0x8000, 0xFF, 0x00)
+	}
+
+	var testCases = []struct {
+		name     string
+		lcdc     uint8
+		setup    func(p *PPU)
+		x, y     int
+		expected uint8
+	}{
+		{"sprite", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 1, 0x00)
+		}, 0, 0, 3},
+		{"position", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 26, 18, 1, 0x00)
+		}, 10, 10, 3},
+		{"left of the sprite", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 26, 18, 1, 0x00)
+		}, 9, 10, 0},
+		{"above the sprite", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 26, 18, 1, 0x00)
+		}, 10, 9, 0},
+		{"partly off-screen", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 9, 1, 1, 0x00)
+		}, 0, 0, 3},
+		{"sprites disabled", 0x91, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 1, 0x00)
+		}, 0, 0, 0},
+		{"transparent", 0x93, func(p *PPU) {
+			background(p)
+			storeSprite(p, 0, 16, 8, 3, 0x00)
+		}, 4, 0, 1},
+		{"X flip", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 3, 0x20)
+		}, 7, 0, 2},
+		{"X flip transparent", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 3, 0x20)
+		}, 0, 0, 0},
+		{"Y flip", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 4, 0x40)
+		}, 0, 7, 3},
+		{"Y flip transparent", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 4, 0x40)
+		}, 0, 0, 0},
+		{"8×16 ignores bit 0 of the tile", 0x97, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 1, 0x00)
+		}, 0, 0, 0},
+		{"8×16 lower half", 0x97, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 1, 0x00)
+		}, 0, 8, 3},
+		{"8×16 below the sprite", 0x97, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 2, 0x00)
+		}, 0, 16, 0},
+		{"8×16 Y flip", 0x97, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 0, 0x40)
+		}, 0, 0, 3},
+		{"8×16 Y flip lower half", 0x97, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 0, 0x40)
+		}, 0, 15, 0},
+		{"OBP0", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 2, 0x00)
+		}, 0, 0, 1},
+		{"OBP1", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 2, 0x10)
+		}, 0, 0, 2},
+		{"priority over background color 0", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 1, 0x80)
+		}, 0, 0, 3},
+		{"priority", 0x93, func(p *PPU) {
+			background(p)
+			storeSprite(p, 0, 16, 8, 1, 0x80)
+		}, 0, 0, 1},
+		{"priority with the background disabled", 0x92, func(p *PPU) {
+			background(p)
+			storeSprite(p, 0, 16, 8, 1, 0x80)
+		}, 0, 0, 3},
+		{"smaller X first", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 9, 1, 0x00)
+			storeSprite(p, 1, 16, 8, 2, 0x00)
+		}, 1, 0, 1},
+		{"OAM order for equal X", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 2, 0x00)
+			storeSprite(p, 1, 16, 8, 1, 0x00)
+		}, 0, 0, 1},
+		{"transparent over another sprite", 0x93, func(p *PPU) {
+			storeSprite(p, 0, 16, 8, 3, 0x00)
+			storeSprite(p, 1, 16, 8, 1, 0x00)
+		}, 4, 0, 3},
+		{"priority over another sprite", 0x93, func(p *PPU) {
+			background(p)
+			storeSprite(p, 0, 16, 8, 2, 0x80)
+			storeSprite(p, 1, 16, 9, 1, 0x00)
+		}, 1, 0, 1},
+		{"10 sprites per line", 0x93, func(p *PPU) {
+			for i := 0; i < 10; i++ {
+				storeSprite(p, i, 16, 0, 1, 0x00)
+			}
+			storeSprite(p, 10, 16, 8, 1, 0x00)
+		}, 0, 0, 0},
+		{"10 sprites on another line", 0x93, func(p *PPU) {
+			for i := 0; i < 10; i++ {
+				storeSprite(p, i, 16, 0, 1, 0x00)
+			}
+			storeSprite(p, 10, 17, 8, 1, 0x00)
+		}, 0, 8, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s LCDC=0x%02X", tc.name, tc.lcdc), func(t *testing.T) {
+			p := New(interrupts.New())
+			storeTile(p, 0x8010, 0xFF, 0xFF)
+			storeTile(p, 0x8020, 0xFF, 0x00)
+			storeTile(p, 0x8030, 0x00, 0xF0)
+			p.Store(0x8040, 0xFF)
+			p.Store(0x8041, 0xFF)
+			tc.setup(p)
+			p.Store(AddressBGP, 0xE4)
+			p.Store(AddressOBP0, 0xE4)
+			p.Store(AddressOBP1, 0x1B)
+			p.Store(AddressLCDC, tc.lcdc)
+			runFrame(p)
+
+			if s := p.Frame()[tc.y][tc.x]; s != tc.expected {
+				t.Errorf("got %d, expected %d", s, tc.expected)
+			}
+		})
+	}
+}
+
+// Returns whether the provided interrupt is requested in IF.
+func requested(ic *interrupts.Controller, i interrupts.Interrupt) bool {
+	return ic.Load(interrupts.AddressIF)&(1<<i) != 0
+}
+
+func TestModes(t *testing.T) {
+	var testCases = []struct {
+		cycles int
+		mode   uint8
+		ly     uint8
+	}{
+		{0, modeHBlank, 0},
+		{1, modeOAMScan, 0},
+		{19, modeOAMScan, 0},
+		{20, modeDrawing, 0},
+		{62, modeDrawing, 0},
+		{63, modeHBlank, 0},
+		{113, modeHBlank, 0},
+		{114, modeOAMScan, 1},
+		{114*144 - 1, modeHBlank, 143},
+		{114 * 144, modeVBlank, 144},
+		{114*154 - 1, modeVBlank, 153},
+		{114 * 154, modeOAMScan, 0},
+		{114*154 + 20, modeDrawing, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("cycles=%d", tc.cycles), func(t *testing.T) {
+			p := New(interrupts.New())
+			p.Store(AddressLCDC, 0x91)
+			for i := 0; i < tc.cycles; i++ {
+				p.Tick()
+			}
+
+			if mode := p.Load(AddressSTAT) & 0x03; mode != tc.mode {
+				t.Errorf("mode: got %d, expected %d", mode, tc.mode)
+			}
+			if ly := p.Load(AddressLY); ly != tc.ly {
+				t.Errorf("LY: got %d, expected %d", ly, tc.ly)
+			}
+		})
+	}
+}
+
+func TestSTAT(t *testing.T) {
+	p := New(interrupts.New())
+	p.Store(AddressSTAT, 0xFF)
+	p.Store(AddressLYC, 1)
+	if stat := p.Load(AddressSTAT); stat != 0xF8 {
+		t.Errorf("got 0x%02X, expected 0xF8", stat)
+	}
+
+	p.Store(AddressLCDC, 0x91)
+	for i := 0; i < cyclesPerLine+1; i++ {
+		p.Tick()
+	}
+	if stat := p.Load(AddressSTAT); stat != 0xFE {
+		t.Errorf("got 0x%02X, expected 0xFE", stat)
+	}
+
+	p.Store(AddressLYC, 2)
+	if stat := p.Load(AddressSTAT); stat != 0xFA {
+		t.Errorf("got 0x%02X, expected 0xFA", stat)
+	}
+}
+
+func TestVBlankInterrupt(t *testing.T) {
+	ic := interrupts.New()
+	p := New(ic)
+	p.Store(AddressLCDC, 0x91)
+	for i := 0; i < cyclesPerLine*Height-1; i++ {
+		p.Tick()
+	}
+	if requested(ic, interrupts.VBlank) {
+		t.Fatalf("got requested before line %d", Height)
+	}
+
+	p.Tick()
+	if !requested(ic, interrupts.VBlank) {
+		t.Errorf("got not requested at line %d", Height)
+	}
+	if requested(ic, interrupts.LCDStat) {
+		t.Errorf("got STAT requested with no source selected")
+	}
+}
+
+func TestSTATInterrupt(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		stat     uint8
+		lyc      uint8
+		lcdc     uint8
+		cycles   int
+		expected bool
+	}{
+		{"HBlank", statHBlank, 0xFF, 0x91, 63, true},
+		{"HBlank not selected", statOAMScan, 0xFF, 0x91, 63, false},
+		{"OAM scan", statOAMScan, 0xFF, 0x91, 114, true},
+		{"VBlank", statVBlank, 0xFF, 0x91, 114 * 144, true},
+		{"LYC", statLYC, 5, 0x91, 114 * 5, true},
+		{"LYC not selected", statHBlank, 5, 0x91, 114 * 5, false},
+		{"HBlank blocked by LYC", statHBlank | statLYC, 0, 0x91, 63, false},
+		{"OAM scan blocked by HBlank", statHBlank | statOAMScan, 0xFF, 0x91, 114, false},
+		{"LCD off", statHBlank | statLYC, 0, 0x11, 63, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s STAT=0x%02X LYC=%d", tc.name, tc.stat, tc.lyc), func(t *testing.T) {
+			ic := interrupts.New()
+			p := New(ic)
+			p.Store(AddressSTAT, tc.stat)
+			p.Store(AddressLYC, tc.lyc)
+			p.Store(AddressLCDC, tc.lcdc)
+			for i := 0; i < tc.cycles-1; i++ {
+				p.Tick()
+			}
+			ic.Acknowledge(interrupts.LCDStat)
+
+			p.Tick()
+			if got := requested(ic, interrupts.LCDStat); got != tc.expected {
+				t.Errorf("got %t, expected %t", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSTATWrite(t *testing.T) {
+	ic := interrupts.New()
+	p := New(ic)
+	p.Store(AddressLCDC, 0x91)
+	for i := 0; i < 70; i++ {
+		p.Tick()
+	}
+	if requested(ic, interrupts.LCDStat) {
+		t.Fatalf("got requested with no source selected")
+	}
+
+	p.Store(AddressSTAT, statHBlank)
+	if !requested(ic, interrupts.LCDStat) {
+		t.Fatalf("got not requested on selecting a condition that holds")
+	}
+
+	ic.Acknowledge(interrupts.LCDStat)
+	p.Store(AddressSTAT, statHBlank|statLYC)
+	if requested(ic, interrupts.LCDStat) {
+		t.Errorf("got requested while the line was already high")
+	}
+}
+
+func TestLCDOff(t *testing.T) {
+	p := New(interrupts.New())
+	p.Store(AddressBGP, 0xFF)
+	p.Store(AddressLCDC, 0x91)
+	runFrame(p)
+
+	if s := p.Frame()[0][0]; s != 3 {
+		t.Fatalf("got %d, expected 3", s)
+	}
+
+	for i := 0; i < cyclesPerLine*3; i++ {
+		p.Tick()
+	}
+	if ly := p.Load(AddressLY); ly != 3 {
+		t.Errorf("LY: got %d, expected 3", ly)
+	}
+
+	p.Store(AddressLCDC, 0x11)
+	if ly := p.Load(AddressLY); ly != 0 {
+		t.Errorf("LY: got %d, expected 0", ly)
+	}
+	if s := p.Frame()[0][0]; s != 0 {
+		t.Errorf("got %d, expected 0", s)
+	}
+
+	runFrame(p)
+	if ly := p.Load(AddressLY); ly != 0 {
+		t.Errorf("LY: got %d, expected 0", ly)
+	}
+}
+
+func TestImage(t *testing.T) {
+	f := &Frame{}
+	f[1][2] = 3
+
+	img := f.Image()
+	if c := img.ColorIndexAt(2, 1); c != 3 {
+		t.Errorf("got %d, expected 3", c)
+	}
+	if c := img.ColorIndexAt(1, 2); c != 0 {
+		t.Errorf("got %d, expected 0", c)
+	}
+}
@@ -0,0 +1,119 @@
+// Package serial implements the Game Boy serial port: the SB and SC
+// registers, through which bytes are shifted out over the link cable, and the
+// serial interrupt. No peer is ever connected, but the bytes sent can be
+// captured, which is how test ROMs report their results.
+package serial
+
+import (
+	"io"
+
+	"github.com/loizoskounios/game-boy-emulator/interrupts"
+)
+
+// Addresses of the serial registers.
+const (
+	// AddressSB is the address of the serial transfer data register.
+	AddressSB uint16 = 0xFF01
+	// AddressSC is the address of the serial transfer control register.
+	AddressSC uint16 = 0xFF02
+)
+
+// Bits of SC.
+const (
+	scTransfer      uint8 = 1 << 7
+	scInternalClock uint8 = 1 << 0
+	scBits                = scTransfer | scInternalClock
+)
+
+// cyclesPerBit is the number of machine cycles it takes the internal clock,
+// running at 8192 Hz, to shift a bit out.
+const cyclesPerBit = 128
+
+// Serial is the Game Boy serial port. A transfer started with the internal
+// clock shifts the 8 bits of SB out in 1024 machine cycles, shifting in 1s
+// since there is no peer, then requests a serial interrupt. A transfer
+// started with the external clock never completes.
+type Serial struct {
+	sb uint8
+	// sc holds the bits of SC that are not unused.
+	sc uint8
+
+	// bits is the number of bits left to shift out, and cycles the number of
+	// machine cycles spent on the current one.
+	bits   int
+	cycles int
+
+	out        io.Writer
+	interrupts *interrupts.Controller
+}
+
+// New returns a pointer to a new serial port that requests its interrupt
+// through the provided interrupt controller.
+func New(ic *interrupts.Controller) *Serial {
+	return &Serial{interrupts: ic}
+}
+
+// Reset cancels any transfer and clears SB and SC. The writer the port is
+// connected to is kept.
+func (s *Serial) Reset() {
+	*s = Serial{out: s.out, interrupts: s.interrupts}
+}
+
+// Connect makes the serial port write every byte it starts sending to the
+// provided writer, or to nowhere if it is nil. Write errors are ignored.
+func (s *Serial) Connect(w io.Writer) {
+	s.out = w
+}
+
+// Tick advances a transfer running on the internal clock by one machine
+// cycle.
+func (s *Serial) Tick() {
+	if s.sc != scBits {
+		return
+	}
+
+	if s.cycles++; s.cycles < cyclesPerBit {
+		return
+	}
+
+	s.cycles = 0
+	s.sb = s.sb<<1 | 0x01
+
+	if s.bits--; s.bits == 0 {
+		s.sc &^= scTransfer
+		s.interrupts.Request(interrupts.Serial)
+	}
+}
+
+// Load returns the contents of the provided serial register. The unused bits
+// of SC read as 1.
+func (s *Serial) Load(addr uint16) uint8 {
+	switch addr {
+	case AddressSB:
+		return s.sb
+	case AddressSC:
+		return s.sc | ^scBits
+	}
+
+	return 0xFF
+}
+
+// Store writes the provided serial register. Setting bit 7 of SC starts a
+// transfer of the contents of SB.
+func (s *Serial) Store(addr uint16, b uint8) {
+	switch addr {
+	case AddressSB:
+		s.sb = b
+	case AddressSC:
+		s.sc = b & scBits
+		if s.sc&scTransfer == 0 {
+			return
+		}
+
+		s.bits = 8
+		s.cycles = 0
+		if s.out != nil {
+			s.out.Write([]uint8{s.sb})
+		}
+	}
+}
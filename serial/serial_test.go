@@ -0,0 +1,55 @@
+package serial
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/loizoskounios/game-boy-emulator/interrupts"
+)
+
+func TestTransfer(t *testing.T) {
+	var testCases = []struct {
+		sc       uint8
+		cycles   int
+		sb       uint8
+		done     bool
+		expected uint8
+	}{
+		{0x81, 0, 0x41, false, 0xFF},
+		{0x81, cyclesPerBit - 1, 0x41, false, 0xFF},
+		{0x81, cyclesPerBit, 0x83, false, 0xFF},
+		{0x81, 8*cyclesPerBit - 1, 0xFF, false, 0xFF},
+		{0x81, 8 * cyclesPerBit, 0xFF, true, 0x7F},
+		{0x80, 8 * cyclesPerBit, 0x41, false, 0xFE},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("SC=0x%02X cycles=%d", tc.sc, tc.cycles), func(t *testing.T) {
+			var buf bytes.Buffer
+			ic := interrupts.New()
+			ic.Store(interrupts.AddressIE, 0xFF)
+
+			s := New(ic)
+			s.Connect(&buf)
+			s.Store(AddressSB, 0x41)
+			s.Store(AddressSC, tc.sc)
+			for i := 0; i < tc.cycles; i++ {
+				s.Tick()
+			}
+
+			if sb := s.Load(AddressSB); sb != tc.sb {
+				t.Errorf("SB: got 0x%02X, expected 0x%02X", sb, tc.sb)
+			}
+			if sc := s.Load(AddressSC); sc != tc.expected {
+				t.Errorf("SC: got 0x%02X, expected 0x%02X", sc, tc.expected)
+			}
+			if i, ok := ic.Pending(); ok != tc.done || ok && i != interrupts.Serial {
+				t.Errorf("interrupt: got %v %t, expected %t", i, ok, tc.done)
+			}
+			if out := buf.String(); out != "A" {
+				t.Errorf("output: got %q, expected %q", out, "A")
+			}
+		})
+	}
+}
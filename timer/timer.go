@@ -0,0 +1,144 @@
+// Package timer implements the Game Boy timer: the DIV, TIMA, TMA and TAC
+// registers, and the timer interrupt.
+package timer
+
+import "github.com/loizoskounios/game-boy-emulator/interrupts"
+
+// Addresses of the timer registers.
+const (
+	AddressDIV  uint16 = 0xFF04
+	AddressTIMA uint16 = 0xFF05
+	AddressTMA  uint16 = 0xFF06
+	AddressTAC  uint16 = 0xFF07
+)
+
+// Bits of TAC. Only the lower 3 bits exist; the rest read as 1.
+const (
+	tacEnable uint8 = 1 << 2
+	tacClock  uint8 = 0x03
+	tacBits   uint8 = 0x07
+)
+
+// Bit of the internal counter whose falling edge increments TIMA, for each
+// clock selected through TAC: 4096 Hz, 262144 Hz, 65536 Hz and 16384 Hz.
+var clockBits = [4]uint16{1 << 9, 1 << 3, 1 << 5, 1 << 7}
+
+// Number of clock periods in a machine cycle.
+const clocksPerCycle = 4
+
+// Timer is the Game Boy timer. DIV is the upper byte of a 16-bit counter
+// incremented every clock period. TIMA is incremented every time the counter
+// bit selected through TAC falls while the timer is enabled, and is reloaded
+// with TMA one machine cycle after it overflows, requesting an interrupt.
+type Timer struct {
+	counter uint16
+	tima    uint8
+	tma     uint8
+	tac     uint8
+
+	// overflow is true for the machine cycle following an overflow of TIMA,
+	// during which TIMA reads as 0x00.
+	overflow bool
+	// reloaded is true for the machine cycle during which TIMA is reloaded
+	// with TMA. Writes to TIMA are ignored, and writes to TMA go to TIMA too.
+	reloaded bool
+
+	interrupts *interrupts.Controller
+}
+
+// New returns a pointer to a new timer that requests its interrupt through the
+// provided interrupt controller.
+func New(ic *interrupts.Controller) *Timer {
+	return &Timer{interrupts: ic}
+}
+
+// Reset puts the timer back in its power-up state.
+func (t *Timer) Reset() {
+	*t = Timer{interrupts: t.interrupts}
+}
+
+// Tick advances the timer by one machine cycle.
+func (t *Timer) Tick() {
+	t.reloaded = false
+	if t.overflow {
+		t.overflow = false
+		t.reloaded = true
+		t.tima = t.tma
+		t.interrupts.Request(interrupts.Timer)
+	}
+
+	signal := t.signal()
+	t.counter += clocksPerCycle
+	t.detectEdge(signal)
+}
+
+// Returns whether the counter bit selected through TAC is set while the timer
+// is enabled.
+func (t *Timer) signal() bool {
+	return t.tac&tacEnable != 0 && t.counter&clockBits[t.tac&tacClock] != 0
+}
+
+// Increments TIMA if the signal driving it went from high to low.
+func (t *Timer) detectEdge(before bool) {
+	if !before || t.signal() {
+		return
+	}
+
+	if t.tima++; t.tima == 0 {
+		t.overflow = true
+	}
+}
+
+// ResetDIV resets the internal counter, as writing to DIV and executing STOP
+// do. This can increment TIMA if the selected counter bit was set.
+func (t *Timer) ResetDIV() {
+	signal := t.signal()
+	t.counter = 0
+	t.detectEdge(signal)
+}
+
+// SetDIV sets the upper byte of the internal counter, which cannot be done
+// through writes to DIV. It is meant for restoring the state the boot ROM
+// leaves the timer in.
+func (t *Timer) SetDIV(b uint8) {
+	t.counter = uint16(b) << 8
+}
+
+// Load returns the contents of the register at the provided address.
+func (t *Timer) Load(addr uint16) uint8 {
+	switch addr {
+	case AddressDIV:
+		return uint8(t.counter >> 8)
+	case AddressTIMA:
+		return t.tima
+	case AddressTMA:
+		return t.tma
+	case AddressTAC:
+		return t.tac | ^tacBits
+	default:
+		return 0xFF
+	}
+}
+
+// Store saves the provided value into the register at the provided address.
+// Writing to TIMA during the cycle after it overflowed cancels the reload.
+func (t *Timer) Store(addr uint16, b uint8) {
+	switch addr {
+	case AddressDIV:
+		t.ResetDIV()
+	case AddressTIMA:
+		if !t.reloaded {
+			t.tima = b
+			t.overflow = false
+		}
+	case AddressTMA:
+		t.tma = b
+		if t.reloaded {
+			t.tima = b
+		}
+	case AddressTAC:
+		signal := t.signal()
+		t.tac = b & tacBits
+		t.detectEdge(signal)
+	}
+}
@@ -0,0 +1,118 @@
+package timer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/loizoskounios/game-boy-emulator/interrupts"
+)
+
+func TestDIV(t *testing.T) {
+	timer := New(interrupts.New())
+
+	for i := 0; i < 64*3; i++ {
+		timer.Tick()
+	}
+	if div := timer.Load(AddressDIV); div != 3 {
+		t.Errorf("got %d, expected 3", div)
+	}
+
+	timer.Store(AddressDIV, 0xAB)
+	if div := timer.Load(AddressDIV); div != 0 {
+		t.Errorf("after write: got %d, expected 0", div)
+	}
+}
+
+func TestTIMA(t *testing.T) {
+	var testCases = []struct {
+		tac    uint8
+		cycles int
+	}{
+		{0x04, 256},
+		{0x05, 4},
+		{0x06, 16},
+		{0x07, 64},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TAC=0x%02X", tc.tac), func(t *testing.T) {
+			timer := New(interrupts.New())
+			timer.Store(AddressTAC, tc.tac)
+
+			for i := 0; i < tc.cycles*10; i++ {
+				timer.Tick()
+			}
+			if tima := timer.Load(AddressTIMA); tima != 10 {
+				t.Errorf("got %d, expected 10", tima)
+			}
+		})
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	ic := interrupts.New()
+	timer := New(ic)
+	timer.Store(AddressTMA, 0x80)
+	timer.Store(AddressTIMA, 0xFF)
+	timer.Store(AddressTAC, 0x05)
+
+	for i := 0; i < 4; i++ {
+		timer.Tick()
+	}
+
+	// TIMA reads as 0x00 for one cycle before being reloaded.
+	if tima := timer.Load(AddressTIMA); tima != 0x00 {
+		t.Errorf("got 0x%02X, expected 0x00", tima)
+	}
+	if _, ok := ic.Pending(); ok {
+		t.Error("got interrupt requested, expected none yet")
+	}
+
+	timer.Tick()
+	if tima := timer.Load(AddressTIMA); tima != 0x80 {
+		t.Errorf("got 0x%02X, expected 0x80", tima)
+	}
+	if b := ic.Load(interrupts.AddressIF); b&0x04 == 0 {
+		t.Errorf("IF: got 0x%02X, expected timer bit set", b)
+	}
+
+	// Writes to TIMA during the reload cycle are ignored.
+	timer.Store(AddressTIMA, 0x12)
+	if tima := timer.Load(AddressTIMA); tima != 0x80 {
+		t.Errorf("got 0x%02X, expected 0x80", tima)
+	}
+}
+
+func TestOverflowCancelled(t *testing.T) {
+	ic := interrupts.New()
+	timer := New(ic)
+	timer.Store(AddressTIMA, 0xFF)
+	timer.Store(AddressTAC, 0x05)
+
+	for i := 0; i < 4; i++ {
+		timer.Tick()
+	}
+	timer.Store(AddressTIMA, 0x12)
+	timer.Tick()
+
+	if tima := timer.Load(AddressTIMA); tima != 0x12 {
+		t.Errorf("got 0x%02X, expected 0x12", tima)
+	}
+	if b := ic.Load(interrupts.AddressIF); b&0x04 != 0 {
+		t.Errorf("IF: got 0x%02X, expected timer bit reset", b)
+	}
+}
+
+func TestResetDIVEdge(t *testing.T) {
+	timer := New(interrupts.New())
+	timer.Store(AddressTAC, 0x05)
+
+	// Bit 3 of the counter is set after 2 cycles. Resetting the counter then
+	// makes it fall, incrementing TIMA.
+	timer.Tick()
+	timer.Tick()
+	timer.Store(AddressDIV, 0x00)
+	if tima := timer.Load(AddressTIMA); tima != 1 {
+		t.Errorf("got %d, expected 1", tima)
+	}
+}